@@ -5,53 +5,144 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
+	"slidev-studio-ai/internal/ai"
 	"slidev-studio-ai/internal/config"
+	"slidev-studio-ai/internal/httpapi"
+	"slidev-studio-ai/internal/mcp"
+	"slidev-studio-ai/internal/search"
 	"slidev-studio-ai/internal/slidev"
 	"slidev-studio-ai/internal/updater"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// EventConfigChanged is emitted to the frontend with the changed config
+// sections whenever settings change
+const EventConfigChanged = "config:changed"
+
+// EventUpdateProgress is emitted with an updater.Progress while an update
+// downloads
+const EventUpdateProgress = "update:progress"
+
+// EventUpdateAvailable is emitted with an updater.UpdateInfo when the
+// background check finds an update the user has not skipped
+const EventUpdateAvailable = "update:available"
+
+// EventGenerationStage is emitted with the config.Stage GenerateOutline or
+// GenerateSlides is starting
+const EventGenerationStage = "generation:stage"
+
+// EventPreviewMoved is emitted with a PreviewMoved when the preview was
+// restarted because its deck was renamed or moved
+const EventPreviewMoved = "preview:moved"
+
+// PreviewMoved tells the frontend where the preview of a moved deck is now
+type PreviewMoved struct {
+	OldFile string `json:"oldFile"`
+	File    string `json:"file"`
+	URL     string `json:"url"`
+}
+
 // App struct
 type App struct {
-	ctx          context.Context
-	tools        *slidev.Tools
-	slidevServer *slidev.Server
-	version      string
+	ctx               context.Context
+	tools             *slidev.Tools
+	slidevServer      *slidev.Server
+	version           string
+	unsubscribeConfig func()
+	apiServer         *httpapi.Server
+	templates         *slidev.Templates
+	searchIndex       *search.Index
+	updateChecker     *updater.Checker
+	updateMu          sync.Mutex // Guards stopUpdateChecks
+	stopUpdateChecks  context.CancelFunc
 }
 
 // NewApp creates a new App application struct
 func NewApp(version string) *App {
-	// Initialize config
-	_, _ = config.Load()
-
-	// Initialize Slidev Tools in current directory for now
-	cwd, _ := os.Getwd()
-	tools := slidev.NewTools(cwd)
+	// Initialize Slidev Tools in the configured workspace, or the current directory
+	workspace := config.Get().Workspace
+	if workspace == "" {
+		workspace, _ = os.Getwd()
+	}
+	tools := slidev.NewTools(workspace)
+	tools.SetFormatOnSave(config.Get().Editor.FormatOnSave)
 
-	return &App{
+	a := &App{
 		tools:        tools,
 		slidevServer: slidev.NewServer(),
 		version:      version,
 	}
+	tools.OnRename = a.followRename
+	a.searchIndex = search.NewIndex(tools)
+	tools.OnChange = a.searchIndex.Invalidate
+	a.templates = slidev.NewTemplates(filepath.Join(config.Dir(), "templates"))
+	a.apiServer = httpapi.NewServer(a.tools, a.slidevServer, "")
+	a.apiServer.MCP = mcp.NewServer(a.tools, version)
+	a.updateChecker = &updater.Checker{
+		CurrentVersion: version,
+		StatePath:      filepath.Join(updatesDir(), "check.json"),
+		Options:        updateOptions,
+		OnAvailable: func(info *updater.UpdateInfo) {
+			runtime.EventsEmit(a.ctx, EventUpdateAvailable, info)
+		},
+	}
+	return a
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.unsubscribeConfig = config.Subscribe(a.onConfigChanged)
+	a.restartUpdateChecks()
+	a.restartAPI()
+	a.purgeTrash()
 
 	// Create default deck if not exists
-	if _, err := os.Stat(filepath.Join(a.tools.WorkingDir, "slides.md")); os.IsNotExist(err) {
+	if _, err := os.Stat(filepath.Join(a.tools.Dir(), "slides.md")); os.IsNotExist(err) {
 		a.tools.CreateDeck("Slidev Studio AI", "seriph")
 	}
 }
 
+// onConfigChanged re-initialises the components affected by a settings
+// change and tells the frontend, which re-creates its AI providers
+func (a *App) onConfigChanged(ev config.ChangeEvent) {
+	if ev.Changed(config.SectionWorkspace) {
+		dir := ev.New.Workspace
+		if dir == "" {
+			dir, _ = os.Getwd()
+		}
+		if dir != a.tools.Dir() {
+			// The running preview serves a deck from the old workspace
+			_ = a.slidevServer.Stop()
+			a.tools.SetWorkingDir(dir)
+			a.purgeTrash()
+		}
+	}
+	if ev.Changed(config.SectionTrash) {
+		a.purgeTrash()
+	}
+	if ev.Changed(config.SectionEditor) {
+		a.tools.SetFormatOnSave(ev.New.Editor.FormatOnSave)
+	}
+	if ev.Changed(config.SectionUpdates) {
+		a.restartUpdateChecks()
+	}
+	if ev.Changed(config.SectionAPI) {
+		a.restartAPI()
+	}
+	runtime.EventsEmit(a.ctx, EventConfigChanged, ev.Sections)
+}
+
 // StartSlidevServer starts the slidev server for a specific file and returns the URL
 func (a *App) StartSlidevServer(filename string) (string, error) {
 	if filename == "" {
 		filename = "slides.md"
 	}
-	return a.slidevServer.Start(a.tools.WorkingDir, filename)
+	return a.slidevServer.Start(a.tools.Dir(), filename)
 }
 
 // GetSlidevUrl returns the running slidev server URL
@@ -74,6 +165,89 @@ func (a *App) GetSettings() config.Config {
 	return config.Get()
 }
 
+// GetSettingSources reports where each overridable setting came from
+// (default, file, env or flag)
+func (a *App) GetSettingSources() []config.Setting {
+	return config.Settings()
+}
+
+// ListProviderProfiles returns all named AI provider profiles
+func (a *App) ListProviderProfiles() []config.ProviderProfile {
+	return config.ListProfiles()
+}
+
+// SaveProviderProfile creates or updates an AI provider profile
+func (a *App) SaveProviderProfile(profile config.ProviderProfile) (config.ProviderProfile, error) {
+	return config.SaveProfile(profile)
+}
+
+// DeleteProviderProfile deletes an AI provider profile
+func (a *App) DeleteProviderProfile(id string) error {
+	return config.DeleteProfile(id)
+}
+
+// SetActiveProviderProfile selects the default AI provider profile
+func (a *App) SetActiveProviderProfile(id string) error {
+	return config.SetActiveProfile(id)
+}
+
+// SetStageProviderProfile binds a pipeline stage ("preprocess", "outline",
+// "slides", "chat") to a profile; an empty id restores the default
+func (a *App) SetStageProviderProfile(stage string, id string) error {
+	return config.SetStageProfile(config.Stage(stage), id)
+}
+
+// GetStageProviderProfile returns the profile a pipeline stage should use
+func (a *App) GetStageProviderProfile(stage string) (config.ProviderProfile, error) {
+	return config.ProfileForStage(config.Stage(stage))
+}
+
+// ListStyles returns all prompt styles (builtin + custom)
+func (a *App) ListStyles() []config.PromptStyle {
+	return config.ListStyles()
+}
+
+// CreateStyle adds a new custom prompt style
+func (a *App) CreateStyle(style config.PromptStyle) (config.PromptStyle, error) {
+	return config.CreateStyle(style)
+}
+
+// UpdateStyle saves changes to a prompt style
+func (a *App) UpdateStyle(style config.PromptStyle) error {
+	return config.UpdateStyle(style)
+}
+
+// DeleteStyle deletes a custom prompt style
+func (a *App) DeleteStyle(id string) error {
+	return config.DeleteStyle(id)
+}
+
+// ResetStyle restores a builtin prompt style to its shipped version
+func (a *App) ResetStyle(id string) (config.PromptStyle, error) {
+	return config.ResetStyle(id)
+}
+
+// DuplicateStyle copies a prompt style into a new custom style
+func (a *App) DuplicateStyle(id string) (config.PromptStyle, error) {
+	return config.DuplicateStyle(id)
+}
+
+// SelectStyle sets the prompt style used for generation
+func (a *App) SelectStyle(id string) error {
+	return config.SelectStyle(id)
+}
+
+// ExportStyles writes the given prompt styles to a shareable style pack file
+func (a *App) ExportStyles(ids []string, path string) error {
+	return config.ExportStyles(ids, path)
+}
+
+// ImportStyles imports a style pack file. strategy is "rename" (default),
+// "overwrite" or "skip" and applies to styles whose ID already exists.
+func (a *App) ImportStyles(path string, strategy string) (*config.ImportResult, error) {
+	return config.ImportStyles(path, config.ConflictStrategy(strategy))
+}
+
 // ListProjects returns a list of local projects
 func (a *App) ListProjects() []slidev.Project {
 	projects, err := a.tools.ListProjects()
@@ -84,14 +258,129 @@ func (a *App) ListProjects() []slidev.Project {
 	return projects
 }
 
-// CreateProject creates a new project file
+// maxSearchResults bounds the results Search returns
+const maxSearchResults = 50
+
+// Search finds slides across all projects. Words match words starting with
+// them, "quoted text" matches a phrase, and field filters such as
+// title:revenue, notes:emea, project:talks or layout:two-cols narrow the
+// results.
+func (a *App) Search(query string) ([]search.Result, error) {
+	return a.searchIndex.Search(query, maxSearchResults)
+}
+
+// CreateProject creates a new project file. It fails rather than replace an
+// existing project.
 func (a *App) CreateProject(name string) error {
 	return a.tools.CreateProject(name)
 }
 
-// DeleteProject deletes a project file
+// CreateProjectWithOptions creates a project with a separate title and theme
+// and returns its file name, which is derived from name
+func (a *App) CreateProjectWithOptions(name string, opts slidev.CreateOptions) (string, error) {
+	return a.tools.CreateProjectWithOptions(name, opts)
+}
+
+// DeleteProject moves a project file to the trash
 func (a *App) DeleteProject(name string) error {
-	return a.tools.DeleteProject(name)
+	if err := a.tools.DeleteProject(name); err != nil {
+		return err
+	}
+	a.purgeTrash()
+	return nil
+}
+
+// ListTemplates returns the builtin and saved deck templates
+func (a *App) ListTemplates() ([]slidev.Template, error) {
+	return a.templates.List()
+}
+
+// CreateProjectFromTemplate creates a project from a template, filling in its
+// placeholders from vars, and returns the project's file name. The name is
+// sanitized as in CreateProject.
+func (a *App) CreateProjectFromTemplate(name string, template string, vars map[string]string) (string, error) {
+	return a.tools.CreateProjectFromTemplate(a.templates, name, template, vars)
+}
+
+// SaveProjectAsTemplate adds a project to the template gallery
+func (a *App) SaveProjectAsTemplate(name string, templateName string, description string) (slidev.Template, error) {
+	return a.tools.SaveProjectAsTemplate(a.templates, name, templateName, description)
+}
+
+// DeleteTemplate removes a saved template
+func (a *App) DeleteTemplate(id string) error {
+	return a.templates.Delete(id)
+}
+
+// RenameProject gives a project file a new name in the same folder
+func (a *App) RenameProject(name string, newName string) error {
+	return a.tools.RenameProject(name, newName)
+}
+
+// DuplicateProject copies a project and returns the copy's file name. An
+// empty newName picks "<name> (copy).md".
+func (a *App) DuplicateProject(name string, newName string) (string, error) {
+	return a.tools.DuplicateProject(name, newName)
+}
+
+// MoveProject moves a project into a folder of the workspace ("" for the top
+// level) and returns its new file name
+func (a *App) MoveProject(name string, toFolder string) (string, error) {
+	return a.tools.MoveProject(name, toFolder)
+}
+
+// followRename restarts the preview when the deck it serves was renamed or
+// moved, whichever side (GUI, API or AI) did it
+func (a *App) followRename(oldName, newName string) {
+	go func() {
+		url, err := a.slidevServer.Retarget(oldName, newName)
+		if err != nil {
+			fmt.Printf("Error restarting the preview of %s: %v\n", newName, err)
+			return
+		}
+		if url != "" && a.ctx != nil {
+			runtime.EventsEmit(a.ctx, EventPreviewMoved, PreviewMoved{OldFile: oldName, File: newName, URL: url})
+		}
+	}()
+}
+
+// FindReplace replaces text across the slides of a project, or with
+// opts.DryRun previews the per-slide changes
+func (a *App) FindReplace(name string, pattern string, replacement string, opts slidev.FindOptions) (*slidev.FindReplaceResult, error) {
+	return a.tools.FindReplace(name, pattern, replacement, opts)
+}
+
+// UndoHistory lists the operations on a project that can be undone, latest
+// first
+func (a *App) UndoHistory(name string) ([]slidev.UndoEntry, error) {
+	return a.tools.UndoHistory(name)
+}
+
+// Undo reverts the latest undoable operation on a project
+func (a *App) Undo(name string) (slidev.UndoEntry, error) {
+	return a.tools.Undo(name)
+}
+
+// ListTrash returns the deleted projects that can be restored
+func (a *App) ListTrash() ([]slidev.TrashEntry, error) {
+	return a.tools.ListTrash()
+}
+
+// RestoreProject brings a deleted project back and returns its file name
+func (a *App) RestoreProject(id string) (string, error) {
+	return a.tools.RestoreProject(id)
+}
+
+// EmptyTrash permanently deletes all deleted projects
+func (a *App) EmptyTrash() error {
+	return a.tools.EmptyTrash()
+}
+
+// purgeTrash removes deleted projects older than the configured retention
+func (a *App) purgeTrash() {
+	if _, err := a.tools.PurgeTrash(config.Get().Trash.Retention()); err != nil {
+		fmt.Printf("Error purging trash: %v\n", err)
+	}
 }
 
 // ReadSlides reads the content of a specific markdown file
@@ -110,29 +399,308 @@ func (a *App) SaveSlides(filename string, content string) error {
 	return a.tools.SaveSlides(filename, content)
 }
 
-// UpdatePage updates a specific slide page content (Tool Call from AI)
+// UpdatePage writes the content of a page right away. AI tool calls go
+// through ProposePageUpdate instead so the user reviews them.
 func (a *App) UpdatePage(filename string, pageIndex int, markdown string) error {
 	return a.tools.UpdatePage(filename, pageIndex, markdown)
 }
 
-// InsertPage inserts a new slide after a specific index (Tool Call from AI)
+// GetNotes returns the presenter notes of a slide
+func (a *App) GetNotes(filename string, slide int) (string, error) {
+	return a.tools.GetNotes(filename, slide)
+}
+
+// SetNotes replaces the presenter notes of a slide without touching its
+// content; empty text removes them
+func (a *App) SetNotes(filename string, slide int, text string) error {
+	return a.tools.SetNotes(filename, slide, text)
+}
+
+// LintDeck checks a project for Slidev syntax errors and content problems
+func (a *App) LintDeck(filename string) ([]slidev.Diagnostic, error) {
+	return a.tools.LintDeck(filename, slidev.LintOptions{})
+}
+
+// GenerateOutline extracts source cards from text and drafts an outline for
+// the user to review, with the selected prompt style
+func (a *App) GenerateOutline(text string) (*ai.Result, error) {
+	pipeline := ai.NewPipeline(config.SelectedStyle(), "")
+	pipeline.OnStage = func(stage config.Stage) {
+		runtime.EventsEmit(a.ctx, EventGenerationStage, stage)
+	}
+	return pipeline.Draft(a.ctx, text)
+}
+
+// GenerateSlides writes the slides for an outline the user has reviewed,
+// with the selected prompt style and the configured generation options:
+// lint fix rounds and, when enabled, speaker notes
+func (a *App) GenerateSlides(outline ai.Outline, cards []ai.Card, theme string) (*ai.Result, error) {
+	pipeline := ai.NewPipeline(config.SelectedStyle(), theme)
+	pipeline.OnStage = func(stage config.Stage) {
+		runtime.EventsEmit(a.ctx, EventGenerationStage, stage)
+	}
+	return pipeline.Compose(a.ctx, &outline, cards)
+}
+
+// SetGenerationOptions saves the optional steps of deck generation, such as
+// drafting speaker notes and the lint fix rounds
+func (a *App) SetGenerationOptions(opts config.GenerationConfig) error {
+	return config.SetGeneration(opts)
+}
+
+// FormatDeck rewrites a project in the canonical markdown format and
+// reports whether it changed. Undo reverts it.
+func (a *App) FormatDeck(filename string) (bool, error) {
+	return a.tools.FormatDeck(filename)
+}
+
+// ProposeChange stores an AI edit of a whole project as a change set to
+// review instead of writing it. It returns nil when nothing would change.
+func (a *App) ProposeChange(filename string, markdown string, summary string) (*slidev.ChangeSet, error) {
+	return a.tools.ProposeChange(filename, markdown, summary)
+}
+
+// ProposePageUpdate is UpdatePage as a change set to review (Tool Call from AI).
+// Edits in a row extend the same change set.
+func (a *App) ProposePageUpdate(filename string, pageIndex int, markdown string, summary string) (*slidev.ChangeSet, error) {
+	return a.tools.ProposePageUpdate(filename, pageIndex, markdown, summary)
+}
+
+// ProposePageInsert is InsertPage as a change set to review (Tool Call from AI)
+func (a *App) ProposePageInsert(filename string, afterIndex int, layout string, summary string) (*slidev.ChangeSet, error) {
+	return a.tools.ProposePageInsert(filename, afterIndex, layout, summary)
+}
+
+// ProposeTheme is ApplyTheme as a change set to review (Tool Call from AI)
+func (a *App) ProposeTheme(filename string, themeName string, summary string) (*slidev.ChangeSet, error) {
+	return a.tools.ProposeGlobalTheme(filename, themeName, summary)
+}
+
+// PendingContent returns the project as the pending change set the next
+// proposed edit extends would leave it, numbering the pages of that edit
+func (a *App) PendingContent(filename string) (string, error) {
+	return a.tools.PendingContent(filename)
+}
+
+// DeckPages splits slide markdown into the content of its pages, numbered
+// as the page methods number them
+func (a *App) DeckPages(markdown string) []string {
+	return slidev.Pages(markdown)
+}
+
+// ListChangeSets returns the change sets of a project waiting for review
+func (a *App) ListChangeSets(filename string) ([]slidev.ChangeSet, error) {
+	return a.tools.ListChangeSets(filename)
+}
+
+// ApplyChangeSet writes the accepted hunks of a change set as one undoable
+// operation and drops the rest
+func (a *App) ApplyChangeSet(filename string, id string, accepted []int) error {
+	return a.tools.ApplyChangeSet(filename, id, accepted)
+}
+
+// DiscardChangeSet rejects a whole change set
+func (a *App) DiscardChangeSet(filename string, id string) error {
+	return a.tools.DiscardChangeSet(filename, id)
+}
+
+// InsertPage inserts a new slide after a specific index right away. AI tool
+// calls go through ProposePageInsert instead.
 func (a *App) InsertPage(filename string, afterIndex int, layout string) error {
 	return a.tools.InsertPage(filename, afterIndex, layout)
 }
 
-// ApplyTheme applies a global theme to the presentation (Tool Call from AI)
+// ApplyTheme applies a global theme to the presentation right away. AI tool
+// calls go through ProposeTheme instead.
 func (a *App) ApplyTheme(filename string, themeName string) error {
 	return a.tools.ApplyGlobalTheme(filename, themeName)
 }
 
-// CheckForUpdates checks if there is a new version available
+// updateOptions returns the updater options for the current config
+func updateOptions() updater.Options {
+	cfg := config.Get().Updates
+	return updater.Options{
+		Repository:  cfg.Repository,
+		Channel:     updater.Channel(cfg.Channel),
+		SkipVersion: cfg.SkippedVersion,
+	}
+}
+
+// restartUpdateChecks (re)starts the periodic background update check, or
+// stops it when disabled in the settings
+func (a *App) restartUpdateChecks() {
+	a.updateMu.Lock()
+	defer a.updateMu.Unlock()
+	if a.stopUpdateChecks != nil {
+		a.stopUpdateChecks()
+		a.stopUpdateChecks = nil
+	}
+	if config.Get().Updates.DisableAutoCheck {
+		return
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopUpdateChecks = cancel
+	go a.updateChecker.Run(ctx)
+}
+
+// CheckForUpdates checks if there is a new version available on the
+// configured release channel
 func (a *App) CheckForUpdates() (*updater.UpdateInfo, error) {
-	// TODO: Replace with actual owner/repo
-	return updater.CheckForUpdates(a.version, "slidev-studio-ai", "slidev-studio-ai")
+	return a.updateChecker.Check(a.ctx)
+}
+
+// SkipUpdateVersion stops the background check from announcing version
+func (a *App) SkipUpdateVersion(version string) error {
+	cfg := config.Get()
+	cfg.Updates.SkippedVersion = version
+	return config.Save(cfg)
+}
+
+// updatesDir is where downloaded updates are staged
+func updatesDir() string {
+	return filepath.Join(config.Dir(), "updates")
+}
+
+// DownloadUpdate downloads and verifies the newest update on the configured
+// channel and stages it for installation on next launch
+func (a *App) DownloadUpdate() (*updater.StagedUpdate, error) {
+	info, err := a.CheckForUpdates()
+	if err != nil {
+		return nil, err
+	}
+	d := &updater.Downloader{
+		Dir: updatesDir(),
+		OnProgress: func(p updater.Progress) {
+			runtime.EventsEmit(a.ctx, EventUpdateProgress, p)
+		},
+	}
+	return d.Download(a.ctx, info)
+}
+
+// GetStagedUpdate returns the update waiting to be installed, if any
+func (a *App) GetStagedUpdate() (*updater.StagedUpdate, error) {
+	return updater.Pending(updatesDir())
+}
+
+// installPendingUpdate installs a staged update that is newer than version
+// before the window opens. It returns true when the app must exit: an
+// installer that needs the app closed was launched, or the new version was
+// started in its place. Installer updates stay staged until the new version
+// starts.
+func installPendingUpdate(version string) (exit bool) {
+	dir := updatesDir()
+	staged, err := updater.Pending(dir)
+	if err != nil {
+		fmt.Printf("Discarding staged update: %v\n", err)
+		_ = updater.ClearPending(dir)
+		return false
+	}
+	if staged == nil {
+		return false
+	}
+
+	current, errCur := updater.ParseVersion(version)
+	next, errNext := updater.ParseVersion(staged.Version)
+	if errCur != nil || errNext != nil || next.Compare(current) <= 0 {
+		_ = updater.ClearPending(dir)
+		return false
+	}
+
+	if !staged.InstallStarted.IsZero() {
+		// The installer ran, yet this older version started again
+		fmt.Printf("Update %s did not install, discarding it\n", staged.Version)
+		_ = updater.ClearPending(dir)
+		return false
+	}
+
+	if err := updater.MarkInstallStarted(dir); err != nil {
+		fmt.Printf("Error preparing update %s: %v\n", staged.Version, err)
+		return false
+	}
+	quit, err := updater.Install(staged)
+	if err != nil {
+		fmt.Printf("Error installing update %s: %v\n", staged.Version, err)
+		_ = updater.ClearPending(dir)
+		return false
+	}
+	if quit {
+		// The installer replaces the app after it quits. The update is
+		// cleared once the new version starts and finds it not newer.
+		return true
+	}
+	_ = updater.ClearPending(dir)
+	if err := updater.Restart(); err != nil {
+		// The new version is in place and runs from the next launch
+		fmt.Printf("Error restarting into update %s: %v\n", staged.Version, err)
+		return false
+	}
+	return true
+}
+
+// APIInfo tells the settings page how to reach the local HTTP API
+type APIInfo struct {
+	Enabled   bool   `json:"enabled"`
+	URL       string `json:"url"` // Empty while not running
+	Token     string `json:"token"`
+	TokenPath string `json:"tokenPath"`
+}
+
+// restartAPI applies the API settings, starting or stopping the local HTTP API
+func (a *App) restartAPI() {
+	_ = a.apiServer.Stop()
+	cfg := config.Get().API
+	if !cfg.Enabled {
+		return
+	}
+	token, err := httpapi.LoadToken(config.Dir())
+	if err != nil {
+		fmt.Printf("Error loading API token: %v\n", err)
+		return
+	}
+	a.apiServer.SetToken(token)
+	if _, err := a.apiServer.Start(cfg.ListenPort()); err != nil {
+		fmt.Printf("Error starting API server: %v\n", err)
+	}
+}
+
+// GetAPIInfo returns the state and credentials of the local HTTP API
+func (a *App) GetAPIInfo() (*APIInfo, error) {
+	info := &APIInfo{
+		Enabled:   config.Get().API.Enabled,
+		URL:       a.apiServer.URL(),
+		TokenPath: filepath.Join(config.Dir(), httpapi.TokenFile),
+	}
+	if info.Enabled {
+		token, err := httpapi.LoadToken(config.Dir())
+		if err != nil {
+			return nil, err
+		}
+		info.Token = token
+	}
+	return info, nil
+}
+
+// RegenerateAPIToken replaces the API token, locking out existing clients
+func (a *App) RegenerateAPIToken() (string, error) {
+	token, err := httpapi.RegenerateToken(config.Dir())
+	if err != nil {
+		return "", err
+	}
+	a.apiServer.SetToken(token)
+	return token, nil
 }
 
 // shutdown is called when the app terminates
 func (a *App) shutdown(ctx context.Context) {
+	if a.unsubscribeConfig != nil {
+		a.unsubscribeConfig()
+	}
+	a.updateMu.Lock()
+	if a.stopUpdateChecks != nil {
+		a.stopUpdateChecks()
+	}
+	a.updateMu.Unlock()
+	_ = a.apiServer.Stop()
 	if a.slidevServer != nil {
 		_ = a.slidevServer.Stop()
 	}
@@ -2,17 +2,19 @@ import { createOpenAI } from '@ai-sdk/openai';
 import { createAnthropic } from '@ai-sdk/anthropic';
 import { createGoogleGenerativeAI } from '@ai-sdk/google';
 import { createOpenAICompatible } from '@ai-sdk/openai-compatible';
-import { generateText, streamText, stepCountIs } from 'ai';
+import { streamText, stepCountIs } from 'ai';
+import { config } from '../../wailsjs/go/models';
 
-import { BUILTIN_STYLES, DEFAULT_STYLE_ID, getStyleById } from './prompts';
-import type { PromptStyle } from '../types';
+// Default endpoint of a local Ollama server, which speaks the OpenAI API
+const OLLAMA_BASE_URL = 'http://localhost:11434/v1';
 
-// Default prompts (fallback to business style)
-export const OutlinePrompt = BUILTIN_STYLES[0].outlinePrompt;
-export const SlidePrompt = BUILTIN_STYLES[0].slidePrompt;
+// needsApiKey reports whether the provider requires an API key; a local Ollama server does not
+export function needsApiKey(provider: string): boolean {
+  return provider !== 'ollama';
+}
 
-export function getProvider(config: any) {
-  const { provider, apiKey, baseUrl } = config.ai;
+export function getProvider(profile: config.ProviderProfile) {
+  const { provider, apiKey, baseUrl } = profile;
 
   switch (provider) {
     case 'openai':
@@ -34,202 +36,29 @@ export function getProvider(config: any) {
         apiKey,
         baseURL: baseUrl,
       });
+    case 'ollama':
+      return createOpenAICompatible({
+        name: 'ollama',
+        apiKey: apiKey || undefined,
+        baseURL: baseUrl || OLLAMA_BASE_URL,
+      });
     default:
       throw new Error(`Unsupported provider: ${provider}`);
   }
 }
 
-/**
- * Preprocessor: Extract source cards from long text
- * Returns cards[] with card_id, quote, tags, importance
- */
-export async function preprocessText(config: any, text: string) {
-  const provider = getProvider(config);
-  const model = provider(config.ai.model);
-
-  const systemPrompt = `You are an information extractor for slide authoring.
-
-Rules:
-- Only use information explicitly present in the user text.
-- Do NOT add new facts, numbers, examples, or claims.
-- Output **JSON only**. No explanations.
-- Each card quote must be a direct excerpt from the user text.
-
-Output schema (JSON):
-{
-  "cards": [
-    {
-      "card_id": "c001",
-      "quote": "<direct excerpt>",
-      "tags": ["..."],
-      "importance": "high" | "medium" | "low"
-    }
-  ]
-}`;
-
-  const abortController = new AbortController();
-  const timeoutId = setTimeout(() => abortController.abort(), 300000);
-
-  try {
-    const { text: response } = await generateText({
-      model,
-      system: systemPrompt,
-      prompt: `USER_TEXT:\n${text}`,
-      abortSignal: abortController.signal,
-    });
-
-    clearTimeout(timeoutId);
-
-    let content = response.trim();
-    if (content.startsWith('```')) {
-      const lines = content.split('\n');
-      content = lines.slice(1, -1).join('\n');
-      if (content.startsWith('json')) {
-        content = content.substring(4).trim();
-      }
-    }
-
-    try {
-      const result = JSON.parse(content);
-      return result.cards || [];
-    } catch (e) {
-      console.error('Failed to parse preprocessor response:', content);
-      throw new Error('预处理器返回了数据，但格式不正确，请重试');
-    }
-  } catch (e: any) {
-    clearTimeout(timeoutId);
-    if (e.name === 'AbortError') {
-      throw new Error('预处理响应超时（60秒），请检查网络或 API 配置后重试');
-    }
-    throw e;
-  }
-}
-
-/**
- * Estimate page count based on number of cards
- * Clamped to [6, 18] range
- */
-export function estimatePageCount(cardsCount: number): number {
-  // Rough heuristic: ~2-3 cards per content slide
-  // Fixed pages: cover + agenda + summary + qa = 4
-  // Content slides = cardsCount / 2.5
-  const contentSlides = Math.round(cardsCount / 2.5);
-  const totalPages = 4 + contentSlides;
-  
-  // Clamp to [6, 18]
-  return Math.max(6, Math.min(18, totalPages));
-}
-
-export async function generateOutline(
-  config: any,
-  cards: any[],
-  estimatedPages: number,
-  customOutlinePrompt?: string
-) {
-  const provider = getProvider(config);
-  const model = provider(config.ai.model);
-
-  // Use custom prompt if provided, otherwise use default
-  const systemPrompt = customOutlinePrompt || OutlinePrompt;
-
-  // 添加 60 秒超时
-  const abortController = new AbortController();
-  const timeoutId = setTimeout(() => abortController.abort(), 300000);
-
-  try {
-    const { text } = await generateText({
-      model,
-      system: systemPrompt,
-      prompt: `CARDS_JSON:\n${JSON.stringify(cards, null, 2)}\n\nestimated_pages: ${estimatedPages}`,
-      abortSignal: abortController.signal,
-    });
-
-    clearTimeout(timeoutId);
-
-    let content = text.trim();
-    if (content.startsWith('```')) {
-      const lines = content.split('\n');
-      content = lines.slice(1, -1).join('\n');
-      // If it still has JSON tag
-      if (content.startsWith('json')) {
-          content = content.substring(4).trim();
-      }
-    }
-
-    try {
-      return JSON.parse(content);
-    } catch (e) {
-      console.error('Failed to parse AI response:', content);
-      throw new Error('AI 返回了大纲，但格式不正确，请重试');
-    }
-  } catch (e: any) {
-    clearTimeout(timeoutId);
-    if (e.name === 'AbortError') {
-      throw new Error('AI 响应超时（5分钟），请检查网络或 API 配置后重试');
-    }
-    throw e;
-  }
-}
-
-export async function generateSlides(
-  config: any,
-  outline: any,
-  theme: string = 'default',
-  customSlidePrompt?: string
-) {
-  const provider = getProvider(config);
-  const model = provider(config.ai.model);
-
-  // Theme Capabilities (Layouts)
-  const THEME_LAYOUTS: Record<string, string[]> = {
-    'default': ['cover', 'intro', 'center', 'default', 'two-cols', 'end', 'full'],
-    'seriph': ['cover', 'intro', 'center', 'default', 'two-cols', 'end', 'quote', 'image-right'],
-  };
-
-  const layouts = THEME_LAYOUTS[theme] || THEME_LAYOUTS['default'];
-  const themeCapabilities = {
-    layouts,
-  };
-
-  // Use custom prompt if provided, otherwise use default
-  const systemPrompt = customSlidePrompt || SlidePrompt;
-
-  // Build user prompt with structured context
-  const userPrompt = `OUTLINE_JSON:
-${JSON.stringify(outline, null, 2)}
-
-THEME_CAPABILITIES:
-${JSON.stringify(themeCapabilities, null, 2)}
-
-IMPORTANT: You MUST use "theme: ${theme}" in the frontmatter.`;
-
-  const { text } = await generateText({
-    model,
-    system: systemPrompt,
-    prompt: userPrompt,
-  });
-
-  let content = text.trim();
-  if (content.startsWith('```')) {
-    const lines = content.split('\n');
-    content = lines.slice(1, -1).join('\n');
-    if (content.startsWith('markdown')) {
-        content = content.substring(8).trim();
-    }
-  }
-
-  return content;
-}
-
-export function getChatStream(config: any, messages: any[], system: string, tools: any) {
-  const provider = getProvider(config);
-  const model = provider(config.ai.model);
+// getChatStream streams a chat reply from the profile bound to the "chat" stage
+export function getChatStream(profile: config.ProviderProfile, messages: any[], system: string, tools: any) {
+  const provider = getProvider(profile);
+  const model = provider(profile.model);
 
   return streamText({
     model,
     system,
     messages,
     tools,
+    temperature: profile.temperature || undefined, // 0 leaves the provider default
+    maxOutputTokens: profile.maxTokens || undefined,
     stopWhen: stepCountIs(20), // 允许最多20轮工具调用，让 AI 能够执行工具后继续对话
   });
 }
@@ -276,12 +105,10 @@ export interface CoverageReport {
 }
 
 /**
- * Parse slides markdown and extract slide_id anchors
+ * Extract slide_id anchors from the deck's pages
  * Returns array of { slideId, content, index }
  */
-function parseSlidesMarkdown(slidesMarkdown: string): Array<{ slideId: string; content: string; index: number }> {
-  // Split by --- separator (must be on its own line)
-  const slides = slidesMarkdown.split(/\n---\n/);
+function parseSlides(slides: string[]): Array<{ slideId: string; content: string; index: number }> {
   const result: Array<{ slideId: string; content: string; index: number }> = [];
 
   for (let i = 0; i < slides.length; i++) {
@@ -320,14 +147,15 @@ function containsVerbatim(slideContent: string, mustInclude: string): boolean {
 
 /**
  * Validate coverage: check if all outline slides and must_include points are present
+ * pages are the deck's pages as App.DeckPages splits them, so patch indexes match the page tools
  * Returns a structured CoverageReport with proposed patches
  */
-export function validateCoverage(outline: any, slidesMarkdown: string): CoverageReport {
+export function validateCoverage(outline: any, pages: string[]): CoverageReport {
   const outlineVersion = outline.outline_version || 'v1';
   const outlineSlides = outline.slides || [];
   
-  // Parse slides markdown into structured data
-  const deckSlides = parseSlidesMarkdown(slidesMarkdown);
+  // Extract structured data from the pages
+  const deckSlides = parseSlides(pages);
   
   // Create a map for quick lookup
   const deckSlidesMap = new Map<string, { slideId: string; content: string; index: number }>();
@@ -1,8 +1,13 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {updater} from '../models';
-import {config} from '../models';
 import {slidev} from '../models';
+import {config} from '../models';
+import {ai} from '../models';
+import {main} from '../models';
+import {search} from '../models';
+
+export function ApplyChangeSet(arg1:string,arg2:string,arg3:Array<number>):Promise<void>;
 
 export function ApplyTheme(arg1:string,arg2:string):Promise<void>;
 
@@ -10,24 +15,126 @@ export function CheckForUpdates():Promise<updater.UpdateInfo>;
 
 export function CreateProject(arg1:string):Promise<void>;
 
+export function CreateProjectFromTemplate(arg1:string,arg2:string,arg3:Record<string, string>):Promise<string>;
+
+export function CreateProjectWithOptions(arg1:string,arg2:slidev.CreateOptions):Promise<string>;
+
+export function CreateStyle(arg1:config.PromptStyle):Promise<config.PromptStyle>;
+
+export function DeckPages(arg1:string):Promise<Array<string>>;
+
 export function DeleteProject(arg1:string):Promise<void>;
 
+export function DeleteProviderProfile(arg1:string):Promise<void>;
+
+export function DeleteStyle(arg1:string):Promise<void>;
+
+export function DeleteTemplate(arg1:string):Promise<void>;
+
+export function DiscardChangeSet(arg1:string,arg2:string):Promise<void>;
+
+export function DownloadUpdate():Promise<updater.StagedUpdate>;
+
+export function DuplicateProject(arg1:string,arg2:string):Promise<string>;
+
+export function DuplicateStyle(arg1:string):Promise<config.PromptStyle>;
+
+export function EmptyTrash():Promise<void>;
+
+export function ExportStyles(arg1:Array<string>,arg2:string):Promise<void>;
+
+export function FindReplace(arg1:string,arg2:string,arg3:string,arg4:slidev.FindOptions):Promise<slidev.FindReplaceResult>;
+
+export function FormatDeck(arg1:string):Promise<boolean>;
+
+export function GenerateOutline(arg1:string):Promise<ai.Result>;
+
+export function GenerateSlides(arg1:ai.Outline,arg2:Array<ai.Card>,arg3:string):Promise<ai.Result>;
+
+export function GetAPIInfo():Promise<main.APIInfo>;
+
+export function GetNotes(arg1:string,arg2:number):Promise<string>;
+
+export function GetSettingSources():Promise<Array<config.Setting>>;
+
 export function GetSettings():Promise<config.Config>;
 
 export function GetSlidevUrl():Promise<string>;
 
+export function GetStageProviderProfile(arg1:string):Promise<config.ProviderProfile>;
+
+export function GetStagedUpdate():Promise<updater.StagedUpdate>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportStyles(arg1:string,arg2:string):Promise<config.ImportResult>;
+
 export function InsertPage(arg1:string,arg2:number,arg3:string):Promise<void>;
 
+export function LintDeck(arg1:string):Promise<Array<slidev.Diagnostic>>;
+
+export function ListChangeSets(arg1:string):Promise<Array<slidev.ChangeSet>>;
+
 export function ListProjects():Promise<Array<slidev.Project>>;
 
+export function ListProviderProfiles():Promise<Array<config.ProviderProfile>>;
+
+export function ListStyles():Promise<Array<config.PromptStyle>>;
+
+export function ListTemplates():Promise<Array<slidev.Template>>;
+
+export function ListTrash():Promise<Array<slidev.TrashEntry>>;
+
+export function MoveProject(arg1:string,arg2:string):Promise<string>;
+
+export function PendingContent(arg1:string):Promise<string>;
+
+export function ProposeChange(arg1:string,arg2:string,arg3:string):Promise<slidev.ChangeSet>;
+
+export function ProposePageInsert(arg1:string,arg2:number,arg3:string,arg4:string):Promise<slidev.ChangeSet>;
+
+export function ProposePageUpdate(arg1:string,arg2:number,arg3:string,arg4:string):Promise<slidev.ChangeSet>;
+
+export function ProposeTheme(arg1:string,arg2:string,arg3:string):Promise<slidev.ChangeSet>;
+
 export function ReadSlides(arg1:string):Promise<string>;
 
+export function RegenerateAPIToken():Promise<string>;
+
+export function RenameProject(arg1:string,arg2:string):Promise<void>;
+
+export function ResetStyle(arg1:string):Promise<config.PromptStyle>;
+
+export function RestoreProject(arg1:string):Promise<string>;
+
+export function SaveProjectAsTemplate(arg1:string,arg2:string,arg3:string):Promise<slidev.Template>;
+
+export function SaveProviderProfile(arg1:config.ProviderProfile):Promise<config.ProviderProfile>;
+
 export function SaveSettings(arg1:config.Config):Promise<void>;
 
 export function SaveSlides(arg1:string,arg2:string):Promise<void>;
 
+export function Search(arg1:string):Promise<Array<search.Result>>;
+
+export function SelectStyle(arg1:string):Promise<void>;
+
+export function SetActiveProviderProfile(arg1:string):Promise<void>;
+
+export function SetGenerationOptions(arg1:config.GenerationConfig):Promise<void>;
+
+export function SetNotes(arg1:string,arg2:number,arg3:string):Promise<void>;
+
+export function SetStageProviderProfile(arg1:string,arg2:string):Promise<void>;
+
+export function SkipUpdateVersion(arg1:string):Promise<void>;
+
 export function StartSlidevServer(arg1:string):Promise<string>;
 
+export function Undo(arg1:string):Promise<slidev.UndoEntry>;
+
+export function UndoHistory(arg1:string):Promise<Array<slidev.UndoEntry>>;
+
 export function UpdatePage(arg1:string,arg2:number,arg3:string):Promise<void>;
+
+export function UpdateStyle(arg1:config.PromptStyle):Promise<void>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyChangeSet(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyChangeSet'](arg1, arg2, arg3);
+}
+
 export function ApplyTheme(arg1, arg2) {
   return window['go']['main']['App']['ApplyTheme'](arg1, arg2);
 }
@@ -14,10 +18,90 @@ export function CreateProject(arg1) {
   return window['go']['main']['App']['CreateProject'](arg1);
 }
 
+export function CreateProjectFromTemplate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateProjectFromTemplate'](arg1, arg2, arg3);
+}
+
+export function CreateProjectWithOptions(arg1, arg2) {
+  return window['go']['main']['App']['CreateProjectWithOptions'](arg1, arg2);
+}
+
+export function CreateStyle(arg1) {
+  return window['go']['main']['App']['CreateStyle'](arg1);
+}
+
+export function DeckPages(arg1) {
+  return window['go']['main']['App']['DeckPages'](arg1);
+}
+
 export function DeleteProject(arg1) {
   return window['go']['main']['App']['DeleteProject'](arg1);
 }
 
+export function DeleteProviderProfile(arg1) {
+  return window['go']['main']['App']['DeleteProviderProfile'](arg1);
+}
+
+export function DeleteStyle(arg1) {
+  return window['go']['main']['App']['DeleteStyle'](arg1);
+}
+
+export function DeleteTemplate(arg1) {
+  return window['go']['main']['App']['DeleteTemplate'](arg1);
+}
+
+export function DiscardChangeSet(arg1, arg2) {
+  return window['go']['main']['App']['DiscardChangeSet'](arg1, arg2);
+}
+
+export function DownloadUpdate() {
+  return window['go']['main']['App']['DownloadUpdate']();
+}
+
+export function DuplicateProject(arg1, arg2) {
+  return window['go']['main']['App']['DuplicateProject'](arg1, arg2);
+}
+
+export function DuplicateStyle(arg1) {
+  return window['go']['main']['App']['DuplicateStyle'](arg1);
+}
+
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function ExportStyles(arg1, arg2) {
+  return window['go']['main']['App']['ExportStyles'](arg1, arg2);
+}
+
+export function FindReplace(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['FindReplace'](arg1, arg2, arg3, arg4);
+}
+
+export function FormatDeck(arg1) {
+  return window['go']['main']['App']['FormatDeck'](arg1);
+}
+
+export function GenerateOutline(arg1) {
+  return window['go']['main']['App']['GenerateOutline'](arg1);
+}
+
+export function GenerateSlides(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GenerateSlides'](arg1, arg2, arg3);
+}
+
+export function GetAPIInfo() {
+  return window['go']['main']['App']['GetAPIInfo']();
+}
+
+export function GetNotes(arg1, arg2) {
+  return window['go']['main']['App']['GetNotes'](arg1, arg2);
+}
+
+export function GetSettingSources() {
+  return window['go']['main']['App']['GetSettingSources']();
+}
+
 export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
@@ -26,22 +110,106 @@ export function GetSlidevUrl() {
   return window['go']['main']['App']['GetSlidevUrl']();
 }
 
+export function GetStageProviderProfile(arg1) {
+  return window['go']['main']['App']['GetStageProviderProfile'](arg1);
+}
+
+export function GetStagedUpdate() {
+  return window['go']['main']['App']['GetStagedUpdate']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportStyles(arg1, arg2) {
+  return window['go']['main']['App']['ImportStyles'](arg1, arg2);
+}
+
 export function InsertPage(arg1, arg2, arg3) {
   return window['go']['main']['App']['InsertPage'](arg1, arg2, arg3);
 }
 
+export function LintDeck(arg1) {
+  return window['go']['main']['App']['LintDeck'](arg1);
+}
+
+export function ListChangeSets(arg1) {
+  return window['go']['main']['App']['ListChangeSets'](arg1);
+}
+
 export function ListProjects() {
   return window['go']['main']['App']['ListProjects']();
 }
 
+export function ListProviderProfiles() {
+  return window['go']['main']['App']['ListProviderProfiles']();
+}
+
+export function ListStyles() {
+  return window['go']['main']['App']['ListStyles']();
+}
+
+export function ListTemplates() {
+  return window['go']['main']['App']['ListTemplates']();
+}
+
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
+}
+
+export function MoveProject(arg1, arg2) {
+  return window['go']['main']['App']['MoveProject'](arg1, arg2);
+}
+
+export function PendingContent(arg1) {
+  return window['go']['main']['App']['PendingContent'](arg1);
+}
+
+export function ProposeChange(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ProposeChange'](arg1, arg2, arg3);
+}
+
+export function ProposePageInsert(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ProposePageInsert'](arg1, arg2, arg3, arg4);
+}
+
+export function ProposePageUpdate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ProposePageUpdate'](arg1, arg2, arg3, arg4);
+}
+
+export function ProposeTheme(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ProposeTheme'](arg1, arg2, arg3);
+}
+
 export function ReadSlides(arg1) {
   return window['go']['main']['App']['ReadSlides'](arg1);
 }
 
+export function RegenerateAPIToken() {
+  return window['go']['main']['App']['RegenerateAPIToken']();
+}
+
+export function RenameProject(arg1, arg2) {
+  return window['go']['main']['App']['RenameProject'](arg1, arg2);
+}
+
+export function ResetStyle(arg1) {
+  return window['go']['main']['App']['ResetStyle'](arg1);
+}
+
+export function RestoreProject(arg1) {
+  return window['go']['main']['App']['RestoreProject'](arg1);
+}
+
+export function SaveProjectAsTemplate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveProjectAsTemplate'](arg1, arg2, arg3);
+}
+
+export function SaveProviderProfile(arg1) {
+  return window['go']['main']['App']['SaveProviderProfile'](arg1);
+}
+
 export function SaveSettings(arg1) {
   return window['go']['main']['App']['SaveSettings'](arg1);
 }
@@ -50,10 +218,50 @@ export function SaveSlides(arg1, arg2) {
   return window['go']['main']['App']['SaveSlides'](arg1, arg2);
 }
 
+export function Search(arg1) {
+  return window['go']['main']['App']['Search'](arg1);
+}
+
+export function SelectStyle(arg1) {
+  return window['go']['main']['App']['SelectStyle'](arg1);
+}
+
+export function SetActiveProviderProfile(arg1) {
+  return window['go']['main']['App']['SetActiveProviderProfile'](arg1);
+}
+
+export function SetGenerationOptions(arg1) {
+  return window['go']['main']['App']['SetGenerationOptions'](arg1);
+}
+
+export function SetNotes(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetNotes'](arg1, arg2, arg3);
+}
+
+export function SetStageProviderProfile(arg1, arg2) {
+  return window['go']['main']['App']['SetStageProviderProfile'](arg1, arg2);
+}
+
+export function SkipUpdateVersion(arg1) {
+  return window['go']['main']['App']['SkipUpdateVersion'](arg1);
+}
+
 export function StartSlidevServer(arg1) {
   return window['go']['main']['App']['StartSlidevServer'](arg1);
 }
 
+export function Undo(arg1) {
+  return window['go']['main']['App']['Undo'](arg1);
+}
+
+export function UndoHistory(arg1) {
+  return window['go']['main']['App']['UndoHistory'](arg1);
+}
+
 export function UpdatePage(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdatePage'](arg1, arg2, arg3);
 }
+
+export function UpdateStyle(arg1) {
+  return window['go']['main']['App']['UpdateStyle'](arg1);
+}
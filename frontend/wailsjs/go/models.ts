@@ -1,3 +1,142 @@
+export namespace ai {
+	
+	export class Card {
+	    card_id: string;
+	    quote: string;
+	    tags: string[];
+	    importance: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Card(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.card_id = source["card_id"];
+	        this.quote = source["quote"];
+	        this.tags = source["tags"];
+	        this.importance = source["importance"];
+	    }
+	}
+	export class OutlineSlide {
+	    slide_id: string;
+	    type: string;
+	    title: string;
+	    purpose?: string;
+	    density?: string;
+	    visual_hint?: string;
+	    bullets: string[];
+	    must_include: string[];
+	    source_card_ids?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OutlineSlide(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slide_id = source["slide_id"];
+	        this.type = source["type"];
+	        this.title = source["title"];
+	        this.purpose = source["purpose"];
+	        this.density = source["density"];
+	        this.visual_hint = source["visual_hint"];
+	        this.bullets = source["bullets"];
+	        this.must_include = source["must_include"];
+	        this.source_card_ids = source["source_card_ids"];
+	    }
+	}
+	export class OutlineMeta {
+	    topic: string;
+	    estimated_pages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OutlineMeta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.topic = source["topic"];
+	        this.estimated_pages = source["estimated_pages"];
+	    }
+	}
+	export class Outline {
+	    outline_version: string;
+	    meta: OutlineMeta;
+	    slides: OutlineSlide[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Outline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.outline_version = source["outline_version"];
+	        this.meta = this.convertValues(source["meta"], OutlineMeta);
+	        this.slides = this.convertValues(source["slides"], OutlineSlide);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class Result {
+	    cards: Card[];
+	    outline?: Outline;
+	    slides: string;
+	    notes?: Record<string, string>;
+	    diagnostics?: slidev.Diagnostic[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cards = this.convertValues(source["cards"], Card);
+	        this.outline = this.convertValues(source["outline"], Outline);
+	        this.slides = source["slides"];
+	        this.notes = source["notes"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], slidev.Diagnostic);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace config {
 	
 	export class AIConfig {
@@ -18,6 +157,76 @@ export namespace config {
 	        this.model = source["model"];
 	    }
 	}
+	export class APIConfig {
+	    enabled: boolean;
+	    port?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	    }
+	}
+	export class GenerationConfig {
+	    speakerNotes?: boolean;
+	    fixRounds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GenerationConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.speakerNotes = source["speakerNotes"];
+	        this.fixRounds = source["fixRounds"];
+	    }
+	}
+	export class EditorConfig {
+	    formatOnSave?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.formatOnSave = source["formatOnSave"];
+	    }
+	}
+	export class TrashConfig {
+	    retentionDays?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.retentionDays = source["retentionDays"];
+	    }
+	}
+	export class UpdateConfig {
+	    channel: string;
+	    repository: string;
+	    disableAutoCheck?: boolean;
+	    skippedVersion?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.repository = source["repository"];
+	        this.disableAutoCheck = source["disableAutoCheck"];
+	        this.skippedVersion = source["skippedVersion"];
+	    }
+	}
 	export class PromptStyle {
 	    id: string;
 	    name: string;
@@ -74,9 +283,81 @@ export namespace config {
 		    return a;
 		}
 	}
+	export class ProviderProfile {
+	    id: string;
+	    name: string;
+	    provider: string;
+	    apiKey?: string;
+	    apiKeyRef?: string;
+	    baseUrl: string;
+	    model: string;
+	    temperature: number;
+	    maxTokens: number;
+	    timeout: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProviderProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.provider = source["provider"];
+	        this.apiKey = source["apiKey"];
+	        this.apiKeyRef = source["apiKeyRef"];
+	        this.baseUrl = source["baseUrl"];
+	        this.model = source["model"];
+	        this.temperature = source["temperature"];
+	        this.maxTokens = source["maxTokens"];
+	        this.timeout = source["timeout"];
+	    }
+	}
+	export class ProviderConfig {
+	    profiles: ProviderProfile[];
+	    activeId: string;
+	    stages?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProviderConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profiles = this.convertValues(source["profiles"], ProviderProfile);
+	        this.activeId = source["activeId"];
+	        this.stages = source["stages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Config {
+	    version: number;
+	    workspace?: string;
 	    ai: AIConfig;
+	    providers: ProviderConfig;
 	    prompts: PromptConfig;
+	    updates: UpdateConfig;
+	    api: APIConfig;
+	    trash: TrashConfig;
+	    editor: EditorConfig;
+	    generation: GenerationConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -84,8 +365,16 @@ export namespace config {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.workspace = source["workspace"];
 	        this.ai = this.convertValues(source["ai"], AIConfig);
+	        this.providers = this.convertValues(source["providers"], ProviderConfig);
 	        this.prompts = this.convertValues(source["prompts"], PromptConfig);
+	        this.updates = this.convertValues(source["updates"], UpdateConfig);
+	        this.api = this.convertValues(source["api"], APIConfig);
+	        this.trash = this.convertValues(source["trash"], TrashConfig);
+	        this.editor = this.convertValues(source["editor"], EditorConfig);
+	        this.generation = this.convertValues(source["generation"], GenerationConfig);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -107,11 +396,332 @@ export namespace config {
 		}
 	}
 	
+	
+	export class ImportResult {
+	    imported: string[];
+	    renamed: Record<string, string>;
+	    overwritten: string[];
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.imported = source["imported"];
+	        this.renamed = source["renamed"];
+	        this.overwritten = source["overwritten"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	
+	
+	
+	
+	export class Setting {
+	    field: string;
+	    value: string;
+	    source: string;
+	    env: string;
+	    flag: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Setting(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.value = source["value"];
+	        this.source = source["source"];
+	        this.env = source["env"];
+	        this.flag = source["flag"];
+	    }
+	}
+	
+
+}
+
+export namespace main {
+	
+	export class APIInfo {
+	    enabled: boolean;
+	    url: string;
+	    token: string;
+	    tokenPath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.url = source["url"];
+	        this.token = source["token"];
+	        this.tokenPath = source["tokenPath"];
+	    }
+	}
+
+}
+
+export namespace search {
+	
+	export class Result {
+	    project: string;
+	    slide: number;
+	    title: string;
+	    snippet: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project = source["project"];
+	        this.slide = source["slide"];
+	        this.title = source["title"];
+	        this.snippet = source["snippet"];
+	        this.score = source["score"];
+	    }
+	}
 
 }
 
 export namespace slidev {
 	
+	export class WordChange {
+	    op: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WordChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.text = source["text"];
+	    }
+	}
+	export class FieldChange {
+	    key: string;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class Hunk {
+	    id: number;
+	    kind: string;
+	    slide: number;
+	    newSlide: number;
+	    title: string;
+	    old: string;
+	    new: string;
+	    frontmatter?: FieldChange[];
+	    words?: WordChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Hunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.slide = source["slide"];
+	        this.newSlide = source["newSlide"];
+	        this.title = source["title"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	        this.frontmatter = this.convertValues(source["frontmatter"], FieldChange);
+	        this.words = this.convertValues(source["words"], WordChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChangeSet {
+	    id: string;
+	    file: string;
+	    summary: string;
+	    // Go type: time
+	    created: any;
+	    hunks: Hunk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangeSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.file = source["file"];
+	        this.summary = source["summary"];
+	        this.created = this.convertValues(source["created"], null);
+	        this.hunks = this.convertValues(source["hunks"], Hunk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreateOptions {
+	    title: string;
+	    theme: string;
+	    folder: string;
+	    autoRename: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.theme = source["theme"];
+	        this.folder = source["folder"];
+	        this.autoRename = source["autoRename"];
+	    }
+	}
+	export class Diagnostic {
+	    rule: string;
+	    severity: string;
+	    slide: number;
+	    line: number;
+	    column: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Diagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rule = source["rule"];
+	        this.severity = source["severity"];
+	        this.slide = source["slide"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	export class FindOptions {
+	    regex: boolean;
+	    caseSensitive: boolean;
+	    wholeWord: boolean;
+	    scopes: string[];
+	    includeCode: boolean;
+	    dryRun: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.regex = source["regex"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.wholeWord = source["wholeWord"];
+	        this.scopes = source["scopes"];
+	        this.includeCode = source["includeCode"];
+	        this.dryRun = source["dryRun"];
+	    }
+	}
+	export class SlideChange {
+	    slide: number;
+	    replacements: number;
+	    diff: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SlideChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slide = source["slide"];
+	        this.replacements = source["replacements"];
+	        this.diff = source["diff"];
+	    }
+	}
+	export class FindReplaceResult {
+	    replacements: number;
+	    slides: SlideChange[];
+	    applied: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindReplaceResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.replacements = source["replacements"];
+	        this.slides = this.convertValues(source["slides"], SlideChange);
+	        this.applied = source["applied"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class Project {
 	    id: string;
 	    name: string;
@@ -130,17 +740,200 @@ export namespace slidev {
 	        this.img = source["img"];
 	    }
 	}
+	
+	export class TemplateVariable {
+	    name: string;
+	    label?: string;
+	    default?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.default = source["default"];
+	    }
+	}
+	export class Template {
+	    id: string;
+	    name: string;
+	    description: string;
+	    theme?: string;
+	    variables?: TemplateVariable[];
+	    assets?: string[];
+	    builtin: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Template(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.theme = source["theme"];
+	        this.variables = this.convertValues(source["variables"], TemplateVariable);
+	        this.assets = source["assets"];
+	        this.builtin = source["builtin"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TrashEntry {
+	    id: string;
+	    name: string;
+	    // Go type: time
+	    deletedAt: any;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	        this.size = source["size"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UndoEntry {
+	    id: string;
+	    label: string;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
 export namespace updater {
 	
+	export class StagedUpdate {
+	    version: string;
+	    assetName: string;
+	    path: string;
+	    sha256: string;
+	    // Go type: time
+	    stagedAt: any;
+	    // Go type: time
+	    installStarted?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new StagedUpdate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.assetName = source["assetName"];
+	        this.path = source["path"];
+	        this.sha256 = source["sha256"];
+	        this.stagedAt = this.convertValues(source["stagedAt"], null);
+	        this.installStarted = this.convertValues(source["installStarted"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class UpdateInfo {
 	    Available: boolean;
+	    CurrentVersion: string;
 	    Version: string;
+	    Prerelease: boolean;
+	    AssetName: string;
+	    AssetKind: string;
 	    DownloadURL: string;
+	    NoCompatibleAsset: boolean;
+	    ChecksumsURL: string;
+	    SignatureURL: string;
 	    ReleaseURL: string;
 	    Body: string;
+	    NotesHTML: string;
+	    Skipped: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new UpdateInfo(source);
@@ -149,10 +942,19 @@ export namespace updater {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.Available = source["Available"];
+	        this.CurrentVersion = source["CurrentVersion"];
 	        this.Version = source["Version"];
+	        this.Prerelease = source["Prerelease"];
+	        this.AssetName = source["AssetName"];
+	        this.AssetKind = source["AssetKind"];
 	        this.DownloadURL = source["DownloadURL"];
+	        this.NoCompatibleAsset = source["NoCompatibleAsset"];
+	        this.ChecksumsURL = source["ChecksumsURL"];
+	        this.SignatureURL = source["SignatureURL"];
 	        this.ReleaseURL = source["ReleaseURL"];
 	        this.Body = source["Body"];
+	        this.NotesHTML = source["NotesHTML"];
+	        this.Skipped = source["Skipped"];
 	    }
 	}
 
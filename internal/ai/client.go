@@ -0,0 +1,237 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"slidev-studio-ai/internal/config"
+)
+
+// Default API endpoints used when a profile has no base URL
+var defaultBaseURLs = map[string]string{
+	"openai":    "https://api.openai.com/v1",
+	"anthropic": "https://api.anthropic.com/v1",
+	"google":    "https://generativelanguage.googleapis.com/v1beta",
+	"ollama":    "http://localhost:11434/v1",
+}
+
+// anthropicMaxTokens is sent when a profile leaves max tokens at the
+// provider default; the Anthropic API requires a value
+const anthropicMaxTokens = 8192
+
+// Client sends single-turn completion requests to the provider of a profile.
+// It is the Go counterpart of the frontend's AI SDK providers, for use
+// without a window.
+type Client struct {
+	Profile config.ProviderProfile
+	HTTP    *http.Client // A client with the profile's timeout when nil
+}
+
+// NewClient returns a client for the given provider profile
+func NewClient(profile config.ProviderProfile) *Client {
+	return &Client{
+		Profile: profile,
+		HTTP:    &http.Client{Timeout: profile.RequestTimeout()},
+	}
+}
+
+func (c *Client) baseURL() string {
+	if c.Profile.BaseURL != "" {
+		return strings.TrimRight(c.Profile.BaseURL, "/")
+	}
+	return defaultBaseURLs[c.Profile.Provider]
+}
+
+// Complete returns the model's reply to prompt under the system instructions
+func (c *Client) Complete(ctx context.Context, system, prompt string) (string, error) {
+	if c.Profile.Model == "" {
+		return "", fmt.Errorf("no model configured for profile %q", c.Profile.Name)
+	}
+	switch c.Profile.Provider {
+	case "openai", "openai-compatible", "ollama":
+		return c.completeOpenAI(ctx, system, prompt)
+	case "anthropic":
+		return c.completeAnthropic(ctx, system, prompt)
+	case "google":
+		return c.completeGoogle(ctx, system, prompt)
+	default:
+		return "", fmt.Errorf("unsupported provider: %s", c.Profile.Provider)
+	}
+}
+
+func (c *Client) completeOpenAI(ctx context.Context, system, prompt string) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	body := struct {
+		Model       string    `json:"model"`
+		Messages    []message `json:"messages"`
+		Temperature float64   `json:"temperature,omitempty"`
+		MaxTokens   int       `json:"max_tokens,omitempty"`
+	}{
+		Model:       c.Profile.Model,
+		Messages:    []message{{"system", system}, {"user", prompt}},
+		Temperature: c.Profile.Temperature,
+		MaxTokens:   c.Profile.MaxTokens,
+	}
+	header := http.Header{}
+	if c.Profile.APIKey != "" {
+		header.Set("Authorization", "Bearer "+c.Profile.APIKey)
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+	}
+	if err := c.post(ctx, c.baseURL()+"/chat/completions", header, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no choices", c.Profile.Provider)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (c *Client) completeAnthropic(ctx context.Context, system, prompt string) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	maxTokens := c.Profile.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = anthropicMaxTokens
+	}
+	body := struct {
+		Model       string    `json:"model"`
+		System      string    `json:"system,omitempty"`
+		Messages    []message `json:"messages"`
+		MaxTokens   int       `json:"max_tokens"`
+		Temperature float64   `json:"temperature,omitempty"`
+	}{
+		Model:       c.Profile.Model,
+		System:      system,
+		Messages:    []message{{"user", prompt}},
+		MaxTokens:   maxTokens,
+		Temperature: c.Profile.Temperature,
+	}
+	header := http.Header{}
+	header.Set("x-api-key", c.Profile.APIKey)
+	header.Set("anthropic-version", "2023-06-01")
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := c.post(ctx, c.baseURL()+"/messages", header, body, &resp); err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+func (c *Client) completeGoogle(ctx context.Context, system, prompt string) (string, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role,omitempty"`
+		Parts []part `json:"parts"`
+	}
+	type generationConfig struct {
+		Temperature     float64 `json:"temperature,omitempty"`
+		MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	}
+	body := struct {
+		SystemInstruction *content         `json:"systemInstruction,omitempty"`
+		Contents          []content        `json:"contents"`
+		GenerationConfig  generationConfig `json:"generationConfig"`
+	}{
+		Contents: []content{{Role: "user", Parts: []part{{prompt}}}},
+		GenerationConfig: generationConfig{
+			Temperature:     c.Profile.Temperature,
+			MaxOutputTokens: c.Profile.MaxTokens,
+		},
+	}
+	if system != "" {
+		body.SystemInstruction = &content{Parts: []part{{system}}}
+	}
+	header := http.Header{}
+	header.Set("x-goog-api-key", c.Profile.APIKey)
+
+	var resp struct {
+		Candidates []struct {
+			Content content `json:"content"`
+		} `json:"candidates"`
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", c.baseURL(), url.PathEscape(c.Profile.Model))
+	if err := c.post(ctx, endpoint, header, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("google returned no candidates")
+	}
+	var text strings.Builder
+	for _, p := range resp.Candidates[0].Content.Parts {
+		text.WriteString(p.Text)
+	}
+	return text.String(), nil
+}
+
+// post sends a JSON request and decodes the JSON response into out
+func (c *Client) post(ctx context.Context, endpoint string, header http.Header, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: c.Profile.RequestTimeout()}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.Profile.Provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s request failed: %s: %s", c.Profile.Provider, resp.Status, errorMessage(resp.Body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// errorMessage extracts the error message of a failed API response; all
+// supported providers use {"error": {"message": ...}}
+func errorMessage(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, 64<<10))
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error.Message != "" {
+		return body.Error.Message
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,438 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"slidev-studio-ai/internal/config"
+	"slidev-studio-ai/internal/slidev"
+)
+
+// preprocessPrompt is the system prompt of the preprocess stage
+const preprocessPrompt = `You are an information extractor for slide authoring.
+
+Rules:
+- Only use information explicitly present in the user text.
+- Do NOT add new facts, numbers, examples, or claims.
+- Output **JSON only**. No explanations.
+- Each card quote must be a direct excerpt from the user text.
+
+Output schema (JSON):
+{
+  "cards": [
+    {
+      "card_id": "c001",
+      "quote": "<direct excerpt>",
+      "tags": ["..."],
+      "importance": "high" | "medium" | "low"
+    }
+  ]
+}`
+
+// notesPrompt is the system prompt of the notes stage
+const notesPrompt = `You write speaker notes for a slide deck.
+
+Rules:
+- Write notes for every slide in the outline, 2-5 sentences each, in the language of the outline.
+- Expand on the slide's bullets in the words a presenter would say.
+- Only use facts from the slide's bullets and its source cards. Do NOT add new facts, numbers or claims.
+- Output **JSON only**. No explanations.
+
+Output schema (JSON):
+{
+  "notes": [
+    { "slide_id": "<slide_id from the outline>", "notes": "<speaker notes>" }
+  ]
+}`
+
+// fixPrompt is the system prompt for correcting slides the linter found
+// problems in
+const fixPrompt = `You fix Slidev markdown.
+
+Rules:
+- Fix every listed problem. Lines and columns refer to the markdown as given.
+- Change nothing else: keep the content, order and wording of the slides.
+- Use only the layouts listed in THEME_CAPABILITIES.
+- Output the complete corrected markdown only. No explanations.`
+
+// themeLayouts lists the layouts each theme supports; unknown themes use
+// the default theme's layouts
+var themeLayouts = map[string][]string{
+	"default": {"cover", "intro", "center", "default", "two-cols", "end", "full"},
+	"seriph":  {"cover", "intro", "center", "default", "two-cols", "end", "quote", "image-right"},
+}
+
+// Card is a source excerpt extracted from the user's text
+type Card struct {
+	CardID     string   `json:"card_id"`
+	Quote      string   `json:"quote"`
+	Tags       []string `json:"tags"`
+	Importance string   `json:"importance"`
+}
+
+// Outline is the editable deck structure produced by the outline stage
+type Outline struct {
+	OutlineVersion string         `json:"outline_version"`
+	Meta           OutlineMeta    `json:"meta"`
+	Slides         []OutlineSlide `json:"slides"`
+}
+
+type OutlineMeta struct {
+	Topic          string `json:"topic"`
+	EstimatedPages int    `json:"estimated_pages"`
+}
+
+type OutlineSlide struct {
+	SlideID       string   `json:"slide_id"`
+	Type          string   `json:"type"`
+	Title         string   `json:"title"`
+	Purpose       string   `json:"purpose,omitempty"`
+	Density       string   `json:"density,omitempty"`
+	VisualHint    string   `json:"visual_hint,omitempty"`
+	Bullets       []string `json:"bullets"`
+	MustInclude   []string `json:"must_include"`
+	SourceCardIDs []string `json:"source_card_ids,omitempty"`
+}
+
+// Completer answers a single prompt; *Client implements it
+type Completer interface {
+	Complete(ctx context.Context, system, prompt string) (string, error)
+}
+
+// Pipeline turns free text into a Slidev deck in three stages: extracting
+// source cards, building an outline and writing the slides, optionally
+// followed by drafting speaker notes
+type Pipeline struct {
+	Style config.PromptStyle
+	Theme string // "default" when empty
+	Notes bool   // Draft speaker notes for the slides
+
+	// FixRounds is how many times slides with lint errors or warnings are
+	// sent back to the model for correction; 0 skips linting
+	FixRounds int
+
+	// Model returns the model for a stage. NewPipeline uses the profile
+	// bound to the stage in the config.
+	Model func(stage config.Stage) (Completer, error)
+
+	// OnStage, when set, is called as each stage starts
+	OnStage func(stage config.Stage)
+}
+
+// Result holds the output of every stage of a pipeline run
+type Result struct {
+	Cards   []Card            `json:"cards"`
+	Outline *Outline          `json:"outline"`
+	Slides  string            `json:"slides"`
+	Notes   map[string]string `json:"notes,omitempty"` // Speaker notes by slide ID
+
+	// Diagnostics are the lint problems left after the fix rounds
+	Diagnostics []slidev.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// NewPipeline returns a pipeline using the given style and theme, the
+// configured provider profile of each stage and the configured generation
+// options
+func NewPipeline(style config.PromptStyle, theme string) *Pipeline {
+	return &Pipeline{
+		Style:     style,
+		Theme:     theme,
+		Notes:     config.Get().Generation.SpeakerNotes,
+		FixRounds: config.Get().Generation.LintFixRounds(),
+		Model: func(stage config.Stage) (Completer, error) {
+			profile, err := config.ProfileForStage(stage)
+			if err != nil {
+				return nil, err
+			}
+			return NewClient(profile), nil
+		},
+	}
+}
+
+func (p *Pipeline) complete(ctx context.Context, stage config.Stage, system, prompt string) (string, error) {
+	if p.OnStage != nil {
+		p.OnStage(stage)
+	}
+	model, err := p.Model(stage)
+	if err != nil {
+		return "", err
+	}
+	text, err := model.Complete(ctx, system, prompt)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", stage, err)
+	}
+	return text, nil
+}
+
+// Run executes all stages on text
+func (p *Pipeline) Run(ctx context.Context, text string) (*Result, error) {
+	draft, err := p.Draft(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return p.Compose(ctx, draft.Outline, draft.Cards)
+}
+
+// Draft runs the stages up to the outline: extracting source cards from
+// text and building an outline of the estimated length from them
+func (p *Pipeline) Draft(ctx context.Context, text string) (*Result, error) {
+	cards, err := p.Preprocess(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	outline, err := p.GenerateOutline(ctx, cards, EstimatePageCount(len(cards)))
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Cards: cards, Outline: outline}, nil
+}
+
+// Compose runs the stages after the outline on an outline the user may have
+// edited: writing the slides, the lint fix rounds and the speaker notes
+func (p *Pipeline) Compose(ctx context.Context, outline *Outline, cards []Card) (*Result, error) {
+	slides, err := p.GenerateSlides(ctx, outline)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Cards: cards, Outline: outline, Slides: slides}
+	if p.FixRounds > 0 {
+		if result.Slides, result.Diagnostics, err = p.FixSlides(ctx, slides); err != nil {
+			return nil, err
+		}
+	}
+	if p.Notes {
+		if result.Notes, err = p.GenerateNotes(ctx, outline, cards); err != nil {
+			return nil, err
+		}
+		result.Slides = AddNotes(result.Slides, outline, result.Notes)
+	}
+	return result, nil
+}
+
+// Preprocess extracts source cards from text
+func (p *Pipeline) Preprocess(ctx context.Context, text string) ([]Card, error) {
+	reply, err := p.complete(ctx, config.StagePreprocess, preprocessPrompt, "USER_TEXT:\n"+text)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Cards []Card `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(stripFence(reply, "json")), &result); err != nil {
+		return nil, fmt.Errorf("preprocess: model returned malformed JSON: %w", err)
+	}
+	return result.Cards, nil
+}
+
+// EstimatePageCount guesses a deck length from the number of cards: the four
+// fixed pages plus a content slide per 2-3 cards, clamped to [6, 18]
+func EstimatePageCount(cards int) int {
+	pages := 4 + int(float64(cards)/2.5+0.5)
+	return max(6, min(18, pages))
+}
+
+// GenerateOutline builds an outline from cards with the style's outline prompt
+func (p *Pipeline) GenerateOutline(ctx context.Context, cards []Card, estimatedPages int) (*Outline, error) {
+	cardsJSON, err := json.MarshalIndent(cards, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	prompt := fmt.Sprintf("CARDS_JSON:\n%s\n\nestimated_pages: %d", cardsJSON, estimatedPages)
+	reply, err := p.complete(ctx, config.StageOutline, p.Style.OutlinePrompt, prompt)
+	if err != nil {
+		return nil, err
+	}
+	var outline Outline
+	if err := json.Unmarshal([]byte(stripFence(reply, "json")), &outline); err != nil {
+		return nil, fmt.Errorf("outline: model returned malformed JSON: %w", err)
+	}
+	return &outline, nil
+}
+
+// GenerateSlides writes the slides.md content for an outline with the
+// style's slide prompt
+func (p *Pipeline) GenerateSlides(ctx context.Context, outline *Outline) (string, error) {
+	theme, layouts := p.themeLayouts()
+	outlineJSON, err := json.MarshalIndent(outline, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	capabilities, err := json.MarshalIndent(map[string][]string{"layouts": layouts}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	prompt := fmt.Sprintf("OUTLINE_JSON:\n%s\n\nTHEME_CAPABILITIES:\n%s\n\nIMPORTANT: You MUST use \"theme: %s\" in the frontmatter.",
+		outlineJSON, capabilities, theme)
+
+	reply, err := p.complete(ctx, config.StageSlides, p.Style.SlidePrompt, prompt)
+	if err != nil {
+		return "", err
+	}
+	return stripFence(reply, "markdown"), nil
+}
+
+// themeLayouts returns the pipeline's theme and the layouts it supports
+func (p *Pipeline) themeLayouts() (string, []string) {
+	theme := p.Theme
+	if theme == "" {
+		theme = "default"
+	}
+	layouts, ok := themeLayouts[theme]
+	if !ok {
+		layouts = themeLayouts["default"]
+	}
+	return theme, layouts
+}
+
+// FixSlides lints slides and has the model correct the errors and warnings
+// found, up to FixRounds times. It returns the slides and the problems
+// remaining.
+func (p *Pipeline) FixSlides(ctx context.Context, slides string) (string, []slidev.Diagnostic, error) {
+	_, layouts := p.themeLayouts()
+	// Only the theme's layouts are allowed, so the built-in ones it lacks
+	// are reported too
+	linter := slidev.NewLinter(slidev.LintOptions{})
+	linter.Rules = append(linter.Rules, slidev.Rule{Name: "theme-layout", Check: func(doc *slidev.LintDocument) []slidev.Diagnostic {
+		var diags []slidev.Diagnostic
+		for i, s := range doc.Deck.Slides {
+			if l := s.Layout(); l != "" && !slices.Contains(layouts, l) && slices.Contains(slidev.BuiltinLayouts, l) {
+				diags = append(diags, slidev.Diagnostic{Severity: slidev.SeverityWarning, Slide: i, Line: s.Line, Column: 1,
+					Message: fmt.Sprintf("layout %q is not supported by the theme", l)})
+			}
+		}
+		return diags
+	}})
+	capabilities, err := json.MarshalIndent(map[string][]string{"layouts": layouts}, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+
+	diags := fixable(linter.Lint(slides))
+	for round := 0; round < p.FixRounds && len(diags) > 0; round++ {
+		var problems strings.Builder
+		for _, d := range diags {
+			problems.WriteString("- " + d.String() + "\n")
+		}
+		prompt := fmt.Sprintf("PROBLEMS:\n%s\nTHEME_CAPABILITIES:\n%s\n\nSLIDES_MARKDOWN:\n%s", problems.String(), capabilities, slides)
+		reply, err := p.complete(ctx, config.StageSlides, fixPrompt, prompt)
+		if err != nil {
+			return "", nil, err
+		}
+		slides = stripFence(reply, "markdown")
+		diags = fixable(linter.Lint(slides))
+	}
+	return slides, diags, nil
+}
+
+// fixable drops the content suggestions from diagnostics, leaving what the
+// model should fix
+func fixable(diags []slidev.Diagnostic) []slidev.Diagnostic {
+	var out []slidev.Diagnostic
+	for _, d := range diags {
+		if d.Severity != slidev.SeverityInfo {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// GenerateNotes drafts speaker notes for each slide of an outline from its
+// bullets and source cards, returning them by slide ID
+func (p *Pipeline) GenerateNotes(ctx context.Context, outline *Outline, cards []Card) (map[string]string, error) {
+	// Only the cards the outline cites are relevant to the notes
+	cited := map[string]bool{}
+	for _, s := range outline.Slides {
+		for _, id := range s.SourceCardIDs {
+			cited[id] = true
+		}
+	}
+	var sources []Card
+	for _, c := range cards {
+		if cited[c.CardID] {
+			sources = append(sources, c)
+		}
+	}
+
+	outlineJSON, err := json.MarshalIndent(outline, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	cardsJSON, err := json.MarshalIndent(sources, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	prompt := fmt.Sprintf("OUTLINE_JSON:\n%s\n\nSOURCE_CARDS_JSON:\n%s", outlineJSON, cardsJSON)
+	reply, err := p.complete(ctx, config.StageNotes, notesPrompt, prompt)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Notes []struct {
+			SlideID string `json:"slide_id"`
+			Notes   string `json:"notes"`
+		} `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(stripFence(reply, "json")), &result); err != nil {
+		return nil, fmt.Errorf("notes: model returned malformed JSON: %w", err)
+	}
+	notes := make(map[string]string, len(result.Notes))
+	for _, n := range result.Notes {
+		if text := strings.TrimSpace(n.Notes); n.SlideID != "" && text != "" {
+			notes[n.SlideID] = text
+		}
+	}
+	return notes, nil
+}
+
+// AddNotes writes speaker notes into the slides generated for an outline. A
+// note goes to the slide with the outline slide's title or, failing that, to
+// the slide at the same position. Slides keep notes they already have.
+func AddNotes(slides string, outline *Outline, notes map[string]string) string {
+	deck := slidev.ParseDeck(slides)
+	used := make([]bool, len(deck.Slides))
+	for i, s := range outline.Slides {
+		text := notes[s.SlideID]
+		if text == "" {
+			continue
+		}
+		target := -1
+		for j := range deck.Slides {
+			if !used[j] && strings.EqualFold(strings.TrimSpace(deck.Slides[j].Title()), strings.TrimSpace(s.Title)) {
+				target = j
+				break
+			}
+		}
+		if target < 0 && i < len(deck.Slides) && !used[i] {
+			target = i
+		}
+		if target < 0 {
+			continue
+		}
+		used[target] = true
+		if deck.Slides[target].Notes() == "" {
+			deck.Slides[target].SetNotes(text)
+		}
+	}
+	return deck.String()
+}
+
+// stripFence removes a markdown code fence wrapped around a model reply,
+// including its language tag
+func stripFence(text, lang string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	lines = lines[1:]
+	if strings.HasPrefix(strings.TrimSpace(lines[len(lines)-1]), "```") {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.TrimSpace(strings.TrimPrefix(strings.Join(lines, "\n"), lang))
+}
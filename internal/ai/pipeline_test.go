@@ -0,0 +1,255 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/config"
+)
+
+// fakeModel answers each stage with a canned reply and records the prompts
+type fakeModel struct {
+	replies map[config.Stage]string
+	prompts map[config.Stage]string
+	stage   config.Stage
+}
+
+func (f *fakeModel) Complete(ctx context.Context, system, prompt string) (string, error) {
+	f.prompts[f.stage] = prompt
+	return f.replies[f.stage], nil
+}
+
+func TestPipeline(t *testing.T) {
+	model := &fakeModel{
+		replies: map[config.Stage]string{
+			config.StagePreprocess: "```json\n{\"cards\": [{\"card_id\": \"c001\", \"quote\": \"Go is fast\", \"importance\": \"high\"}]}\n```",
+			config.StageOutline:    `{"outline_version": "v1", "slides": [{"slide_id": "cover", "type": "cover", "title": "Go"}]}`,
+			config.StageSlides:     "```markdown\n---\ntheme: seriph\n---\n\n# Go\n```",
+		},
+		prompts: map[config.Stage]string{},
+	}
+	var stages []config.Stage
+	p := &Pipeline{
+		Style: config.PromptStyle{OutlinePrompt: "outline", SlidePrompt: "slides"},
+		Theme: "seriph",
+		Model: func(stage config.Stage) (Completer, error) {
+			model.stage = stage
+			return model, nil
+		},
+		OnStage: func(stage config.Stage) { stages = append(stages, stage) },
+	}
+
+	result, err := p.Run(context.Background(), "Go is fast. Really.")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(stages) != 3 || stages[2] != config.StageSlides {
+		t.Errorf("unexpected stages %v", stages)
+	}
+	if len(result.Cards) != 1 || result.Cards[0].Quote != "Go is fast" {
+		t.Errorf("unexpected cards %+v", result.Cards)
+	}
+	if result.Outline.Slides[0].SlideID != "cover" {
+		t.Errorf("unexpected outline %+v", result.Outline)
+	}
+	if result.Slides != "---\ntheme: seriph\n---\n\n# Go" {
+		t.Errorf("code fence not stripped: %q", result.Slides)
+	}
+	if !strings.Contains(model.prompts[config.StageOutline], "estimated_pages: 6") {
+		t.Errorf("outline prompt lacks the page estimate: %q", model.prompts[config.StageOutline])
+	}
+	if !strings.Contains(model.prompts[config.StageSlides], `"image-right"`) {
+		t.Errorf("slides prompt lacks the seriph layouts: %q", model.prompts[config.StageSlides])
+	}
+}
+
+func TestPipelineNotes(t *testing.T) {
+	model := &fakeModel{
+		replies: map[config.Stage]string{
+			config.StagePreprocess: `{"cards": [{"card_id": "c001", "quote": "Go compiles fast"}, {"card_id": "c002", "quote": "Unrelated"}]}`,
+			config.StageOutline:    `{"slides": [{"slide_id": "s1", "title": "Go"}, {"slide_id": "s2", "title": "Speed", "bullets": ["Fast builds"], "source_card_ids": ["c001"]}, {"slide_id": "s3", "title": "Thanks"}]}`,
+			config.StageSlides:     "---\ntheme: default\n---\n\n# Go\n\n---\n\n# Fast builds\n\n---\n\n# Speed\n\n<!-- Keep me -->\n",
+			config.StageNotes:      "```json\n{\"notes\": [{\"slide_id\": \"s1\", \"notes\": \"Introduce Go.\"}, {\"slide_id\": \"s2\", \"notes\": \"Builds take seconds.\"}, {\"slide_id\": \"s3\", \"notes\": \"Say thanks.\"}]}\n```",
+		},
+		prompts: map[config.Stage]string{},
+	}
+	p := &Pipeline{
+		Notes: true,
+		Model: func(stage config.Stage) (Completer, error) {
+			model.stage = stage
+			return model, nil
+		},
+	}
+
+	result, err := p.Run(context.Background(), "Go compiles fast.")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Notes) != 3 || result.Notes["s2"] != "Builds take seconds." {
+		t.Errorf("unexpected notes %v", result.Notes)
+	}
+	prompt := model.prompts[config.StageNotes]
+	if !strings.Contains(prompt, "Go compiles fast") || strings.Contains(prompt, "Unrelated") {
+		t.Errorf("notes prompt should hold only the cited cards: %q", prompt)
+	}
+	// Notes go to the slide with the outline title, else the same position,
+	// and never replace existing ones: "Speed" keeps its notes and the third
+	// slide's position is taken by it
+	want := "---\ntheme: default\n---\n\n# Go\n\n<!--\nIntroduce Go.\n-->\n\n---\n\n# Fast builds\n\n---\n\n# Speed\n\n<!-- Keep me -->"
+	if result.Slides != want {
+		t.Errorf("unexpected slides:\n%q\nwant\n%q", result.Slides, want)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	model := &fakeModel{
+		replies: map[config.Stage]string{
+			config.StageSlides: "# Edited title\n",
+			config.StageNotes:  `{"notes": [{"slide_id": "s1", "notes": "Say hello."}]}`,
+		},
+		prompts: map[config.Stage]string{},
+	}
+	var stages []config.Stage
+	p := &Pipeline{
+		Notes: true,
+		Model: func(stage config.Stage) (Completer, error) {
+			model.stage = stage
+			return model, nil
+		},
+		OnStage: func(stage config.Stage) { stages = append(stages, stage) },
+	}
+
+	// The app composes slides from an outline the user edited
+	outline := &Outline{Slides: []OutlineSlide{{SlideID: "s1", Title: "Edited title"}}}
+	result, err := p.Compose(context.Background(), outline, nil)
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if len(stages) != 2 || stages[0] != config.StageSlides || stages[1] != config.StageNotes {
+		t.Errorf("unexpected stages %v", stages)
+	}
+	if !strings.Contains(model.prompts[config.StageSlides], "Edited title") {
+		t.Errorf("slides prompt lacks the edited outline: %q", model.prompts[config.StageSlides])
+	}
+	if want := "# Edited title\n\n<!--\nSay hello.\n-->"; result.Slides != want {
+		t.Errorf("unexpected slides %q, want %q", result.Slides, want)
+	}
+}
+
+func TestFixSlides(t *testing.T) {
+	broken := "---\ntheme: seriph\n---\n\n# Go\n\n---\nlayout: image-left\n---\n\n# Speed\n\n```go\nfmt.Println()\n"
+	fixed := "---\ntheme: seriph\n---\n\n# Go\n\n---\nlayout: two-cols\n---\n\n# Speed\n\n```go\nfmt.Println()\n```\n"
+	var prompts []string
+	replies := []string{broken, "```markdown\n" + fixed + "```"}
+	p := &Pipeline{
+		Theme:     "seriph",
+		FixRounds: 3,
+		Model: func(stage config.Stage) (Completer, error) {
+			return completerFunc(func(system, prompt string) string {
+				prompts = append(prompts, prompt)
+				reply := replies[0]
+				replies = replies[1:]
+				return reply
+			}), nil
+		},
+	}
+
+	// The model first returns the slides unchanged, then fixes them
+	slides, diags, err := p.FixSlides(context.Background(), broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 2 || len(diags) != 0 || slides != strings.TrimSpace(fixed) {
+		t.Fatalf("FixSlides = %q, %v after %d rounds", slides, diags, len(prompts))
+	}
+	if !strings.Contains(prompts[0], "never closed") || !strings.Contains(prompts[0], "not supported by the theme") {
+		t.Errorf("fix prompt lacks the problems: %q", prompts[0])
+	}
+
+	// Rounds are bounded
+	p.FixRounds = 1
+	replies = []string{broken}
+	if _, diags, _ := p.FixSlides(context.Background(), broken); len(diags) == 0 {
+		t.Error("expected the remaining problems")
+	}
+}
+
+type completerFunc func(system, prompt string) string
+
+func (f completerFunc) Complete(ctx context.Context, system, prompt string) (string, error) {
+	return f(system, prompt), nil
+}
+
+func TestEstimatePageCount(t *testing.T) {
+	for cards, want := range map[int]int{0: 6, 10: 8, 20: 12, 100: 18} {
+		if got := EstimatePageCount(cards); got != want {
+			t.Errorf("EstimatePageCount(%d) = %d, want %d", cards, got, want)
+		}
+	}
+}
+
+func TestClientProviders(t *testing.T) {
+	var got struct {
+		path   string
+		header http.Header
+		body   map[string]any
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path, got.header = r.URL.Path, r.Header
+		got.body = nil
+		_ = json.NewDecoder(r.Body).Decode(&got.body)
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/missing/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "no such model"}}`))
+		case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+			w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "openai says hi"}}]}`))
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			w.Write([]byte(`{"content": [{"type": "text", "text": "anthropic says hi"}]}`))
+		case strings.HasSuffix(r.URL.Path, ":generateContent"):
+			w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "google says hi"}]}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		provider string
+		path     string
+		auth     string // Header carrying the API key
+		want     string
+	}{
+		{"openai-compatible", "/chat/completions", "Authorization", "openai says hi"},
+		{"anthropic", "/messages", "X-Api-Key", "anthropic says hi"},
+		{"google", "/models/gemini:generateContent", "X-Goog-Api-Key", "google says hi"},
+	}
+	for _, tt := range tests {
+		c := NewClient(config.ProviderProfile{Provider: tt.provider, APIKey: "secret", BaseURL: srv.URL, Model: "gemini", MaxTokens: 100})
+		reply, err := c.Complete(context.Background(), "be brief", "hello")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.provider, err)
+		}
+		if reply != tt.want || got.path != tt.path {
+			t.Errorf("%s: got %q from %s, want %q from %s", tt.provider, reply, got.path, tt.want, tt.path)
+		}
+		if !strings.Contains(got.header.Get(tt.auth), "secret") {
+			t.Errorf("%s: API key not sent in %s", tt.provider, tt.auth)
+		}
+		if !strings.Contains(mustJSON(got.body), "be brief") || !strings.Contains(mustJSON(got.body), "hello") {
+			t.Errorf("%s: prompts missing from request %v", tt.provider, got.body)
+		}
+	}
+
+	c := NewClient(config.ProviderProfile{Provider: "openai", BaseURL: srv.URL + "/missing", Model: "x"})
+	if _, err := c.Complete(context.Background(), "", "hi"); err == nil || !strings.Contains(err.Error(), "no such model") {
+		t.Errorf("expected the API error message, got %v", err)
+	}
+}
+
+func mustJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
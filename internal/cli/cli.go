@@ -0,0 +1,196 @@
+// Package cli implements the headless subcommands of the app binary, so decks
+// can be produced from scripts and CI without opening a window.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"slidev-studio-ai/internal/config"
+	"slidev-studio-ai/internal/slidev"
+)
+
+// Version is reported by commands that identify the app; main sets it
+var Version = "v0.0.0-dev"
+
+var (
+	// errUsage makes Run print the command usage and exit with code 2
+	errUsage = errors.New("usage")
+	// errFlags reports bad flags; the flag set already printed the problem
+	errFlags = errors.New("invalid flags")
+)
+
+// env is what a command runs with
+type env struct {
+	ctx    context.Context
+	tools  *slidev.Tools
+	stdout io.Writer
+	stderr io.Writer
+	name   string // Command being run
+	usage  string
+}
+
+type command struct {
+	usage   string // Arguments, after the command name
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"new": {
+		usage:   "[-template id] [-title text] [-theme name] [-folder dir] [-auto-rename] <name>",
+		summary: "create a new deck in the workspace",
+		run:     runNew,
+	},
+	"list": {
+		usage:   "[-json]",
+		summary: "list the decks in the workspace",
+		run:     runList,
+	},
+	"generate": {
+		usage:   "-from <notes.txt|-> [-style id] [-theme name] [-out deck.md] [-outline outline.json] [-notes] [-fix-rounds n] [-force]",
+		summary: "generate a deck from notes with the configured AI provider",
+		run:     runGenerate,
+	},
+	"export": {
+		usage:   "[-format pdf|png|pptx|md] [-output path] [-with-clicks] [-range 1,3-5] [-dark] <deck>",
+		summary: "export a deck with slidev export",
+		run:     runExport,
+	},
+	"serve": {
+		usage:   "[deck]",
+		summary: "serve a deck with the Slidev dev server until interrupted",
+		run:     runServe,
+	},
+	"api": {
+		usage:   "[-port n]",
+		summary: "serve the local HTTP API until interrupted",
+		run:     runAPI,
+	},
+	"mcp": {
+		usage:   "",
+		summary: "serve the deck tools to an AI agent over MCP on stdin/stdout",
+		run:     runMCP,
+	},
+	"templates": {
+		usage:   "",
+		summary: "list the deck templates",
+		run:     runTemplates,
+	},
+	"theme": {
+		usage:   "set <deck> <theme>",
+		summary: "change the theme of a deck",
+		run:     runTheme,
+	},
+}
+
+// Run executes the subcommand in args[0] and returns the process exit code.
+// Config must be bound to the command line flags before, as for the GUI.
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	if _, err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "warning: loading config from %s: %v\n", config.Path(), err)
+	}
+	workspace := config.Get().Workspace
+	if workspace == "" {
+		workspace, _ = os.Getwd()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{
+		ctx:    ctx,
+		tools:  slidev.NewTools(workspace),
+		stdout: stdout,
+		stderr: stderr,
+		name:   args[0],
+		usage:  cmd.usage,
+	}
+	err := cmd.run(e, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errFlags):
+		return 2
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "usage: %s %s %s\n", config.AppName, args[0], cmd.usage)
+		return 2
+	default:
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: %s [config flags] <command> [arguments]\n\n", config.AppName)
+	fmt.Fprintln(w, "Without a command the app window opens. Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(w, "  %-9s %s\n            %s %s\n", name, cmd.summary, name, cmd.usage)
+	}
+	fmt.Fprintf(w, "\nRun %s -h to list the config flags.\n", config.AppName)
+}
+
+// newFlagSet returns a flag set for the command that reports errors to stderr
+func (e *env) newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(e.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: %s %s %s\n", config.AppName, e.name, e.usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags anywhere among the arguments and returns the
+// positional ones, so both "export -format png deck.md" and
+// "export deck.md -format png" work
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		} else if err != nil {
+			return nil, errFlags
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// deckFile adds the .md extension to a deck name when missing
+func deckFile(name string) string {
+	if !strings.HasSuffix(name, ".md") {
+		return name + ".md"
+	}
+	return name
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/secrets"
+)
+
+// useTempWorkspace points the config and workspace at temp dirs through the
+// same env vars users would set
+func useTempWorkspace(t *testing.T) string {
+	workspace := t.TempDir()
+	t.Setenv("SLIDEV_AI_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("SLIDEV_AI_WORKSPACE", workspace)
+	t.Setenv(secrets.BackendEnv, "file")
+	return workspace
+}
+
+func run(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = Run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestDeckCommands(t *testing.T) {
+	workspace := useTempWorkspace(t)
+
+	if code, out, errOut := run(t, "new", "talk", "-theme", "default"); code != 0 || !strings.HasSuffix(strings.TrimSpace(out), "talk.md") {
+		t.Fatalf("new: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if code, _, errOut := run(t, "new", "talk"); code != 1 || !strings.Contains(errOut, "already exists") {
+		t.Errorf("new over an existing deck: code %d, stderr %q", code, errOut)
+	}
+
+	code, out, _ := run(t, "list", "-json")
+	var projects []struct{ Name string }
+	if code != 0 || json.Unmarshal([]byte(out), &projects) != nil || len(projects) != 1 || projects[0].Name != "talk.md" {
+		t.Errorf("list -json: code %d, output %q", code, out)
+	}
+
+	if code, _, errOut := run(t, "theme", "set", "talk", "seriph"); code != 0 {
+		t.Fatalf("theme set: code %d, stderr %q", code, errOut)
+	}
+	data, _ := os.ReadFile(filepath.Join(workspace, "talk.md"))
+	if !strings.Contains(string(data), "theme: seriph") {
+		t.Errorf("theme not applied:\n%s", data)
+	}
+
+	if code, _, _ := run(t, "theme", "talk"); code != 2 {
+		t.Errorf("expected usage error, got code %d", code)
+	}
+	if code, _, _ := run(t, "bogus"); code != 2 {
+		t.Errorf("expected unknown command error, got code %d", code)
+	}
+	if code, _, _ := run(t, "export", "-format", "gif", "talk"); code != 1 {
+		t.Errorf("expected unsupported format error, got code %d", code)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	workspace := useTempWorkspace(t)
+
+	// An OpenAI-compatible server answering each pipeline stage
+	brokenSlides, fixRequests := false, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		system := req.Messages[0].Content
+		var reply string
+		switch {
+		case strings.Contains(system, "information extractor"):
+			reply = `{"cards": [{"card_id": "c001", "quote": "CLI decks", "importance": "high"}]}`
+		case strings.Contains(system, "information architect"):
+			reply = `{"outline_version": "v1", "slides": [{"slide_id": "cover", "type": "cover", "title": "CLI decks"}]}`
+		case strings.Contains(system, "speaker notes"):
+			reply = `{"notes": [{"slide_id": "cover", "notes": "Welcome everyone."}]}`
+		case strings.Contains(system, "You fix Slidev markdown"):
+			fixRequests++
+			reply = "---\ntheme: default\n---\n\n# Fixed\n"
+		case brokenSlides:
+			reply = "---\ntheme: default\n---\n\n# CLI decks\n\n```js\nunclosed()\n"
+		default:
+			reply = "---\ntheme: default\n---\n\n<!-- slide_id: cover -->\n# CLI decks\n"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	defer srv.Close()
+	t.Setenv("SLIDEV_AI_PROVIDER", "openai-compatible")
+	t.Setenv("SLIDEV_AI_BASE_URL", srv.URL)
+	t.Setenv("SLIDEV_AI_MODEL", "test")
+
+	notes := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(notes, []byte("We want to build decks from the CLI."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outline := filepath.Join(t.TempDir(), "outline.json")
+
+	code, out, errOut := run(t, "generate", "-from", notes, "-style", "tech", "-outline", outline)
+	if code != 0 {
+		t.Fatalf("generate: code %d, stderr %q", code, errOut)
+	}
+	if strings.TrimSpace(out) != filepath.Join(workspace, "notes.md") {
+		t.Errorf("unexpected output path %q", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(workspace, "notes.md"))
+	if !strings.Contains(string(data), "# CLI decks") {
+		t.Errorf("unexpected deck:\n%s", data)
+	}
+	if data, err := os.ReadFile(outline); err != nil || !strings.Contains(string(data), `"slide_id": "cover"`) {
+		t.Errorf("outline not written: %s (%v)", data, err)
+	}
+
+	// An existing deck is only replaced with -force
+	if err := os.WriteFile(filepath.Join(workspace, "notes.md"), []byte("# Mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, errOut := run(t, "generate", "-from", notes); code != 1 || !strings.Contains(errOut, "already exists") {
+		t.Errorf("generate over an existing deck: code %d, stderr %q", code, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "notes.md")); string(data) != "# Mine\n" {
+		t.Errorf("existing deck overwritten without -force:\n%s", data)
+	}
+	if code, _, errOut := run(t, "generate", "-from", notes, "-force"); code != 0 {
+		t.Errorf("generate -force: code %d, stderr %q", code, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "notes.md")); !strings.Contains(string(data), "# CLI decks") {
+		t.Errorf("existing deck not overwritten with -force:\n%s", data)
+	}
+
+	if strings.Contains(string(data), "Welcome everyone.") {
+		t.Errorf("speaker notes drafted without -notes:\n%s", data)
+	}
+	if code, _, errOut := run(t, "generate", "-from", notes, "-notes", "-out", "with-notes"); code != 0 || !strings.Contains(errOut, "notes...") {
+		t.Fatalf("generate -notes: code %d, stderr %q", code, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "with-notes.md")); !strings.Contains(string(data), "<!--\nWelcome everyone.\n-->") {
+		t.Errorf("speaker notes missing:\n%s", data)
+	}
+
+	// The config switch turns the step on without the flag
+	configPath := os.Getenv("SLIDEV_AI_CONFIG")
+	var cfg map[string]any
+	if data, err := os.ReadFile(configPath); err != nil || json.Unmarshal(data, &cfg) != nil {
+		t.Fatalf("config not readable: %v", err)
+	}
+	cfg["generation"] = map[string]any{"speakerNotes": true}
+	data, _ = json.Marshal(cfg)
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, errOut := run(t, "generate", "-from", notes, "-out", "configured"); code != 0 || !strings.Contains(errOut, "notes...") {
+		t.Errorf("generate with speakerNotes configured: code %d, stderr %q", code, errOut)
+	}
+
+	// Slides with lint errors go back to the model unless turned off
+	brokenSlides = true
+	if code, _, errOut := run(t, "generate", "-from", notes, "-out", "fixed"); code != 0 || fixRequests != 1 {
+		t.Errorf("generate with lint errors: code %d, %d fix requests, stderr %q", code, fixRequests, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "fixed.md")); !strings.Contains(string(data), "# Fixed") {
+		t.Errorf("slides not fixed:\n%s", data)
+	}
+	if code, _, _ := run(t, "generate", "-from", notes, "-out", "unfixed", "-fix-rounds", "0"); code != 0 || fixRequests != 1 {
+		t.Errorf("-fix-rounds 0: code %d, %d fix requests", code, fixRequests)
+	}
+
+	if code, _, errOut := run(t, "generate", "-from", notes, "-style", "nope", "-force"); code != 1 || !strings.Contains(errOut, "not found") {
+		t.Errorf("unknown style: code %d, stderr %q", code, errOut)
+	}
+}
@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"slidev-studio-ai/internal/ai"
+	"slidev-studio-ai/internal/config"
+	"slidev-studio-ai/internal/httpapi"
+	"slidev-studio-ai/internal/mcp"
+	"slidev-studio-ai/internal/slidev"
+)
+
+func runNew(e *env, args []string) error {
+	fs := e.newFlagSet()
+	var opts slidev.CreateOptions
+	fs.StringVar(&opts.Title, "title", "", "title on the cover (the deck name when empty)")
+	fs.StringVar(&opts.Theme, "theme", "", "theme of the new deck (the template's when empty)")
+	fs.StringVar(&opts.Folder, "folder", "", "workspace folder to create the deck in")
+	fs.BoolVar(&opts.AutoRename, "auto-rename", false, `add " (2)" to the name instead of failing when it is taken`)
+	template := fs.String("template", "", "template to start from (see the templates command)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	var filename string
+	if *template != "" {
+		filename = positional[0]
+		if folder := strings.Trim(opts.Folder, "/"); folder != "" {
+			filename = folder + "/" + filename
+		}
+		vars := map[string]string{}
+		if opts.Title != "" {
+			vars["title"] = opts.Title
+		}
+		if opts.Theme != "" {
+			vars["theme"] = opts.Theme
+		}
+		gallery := slidev.NewTemplates(templatesDir())
+		filename, err = e.tools.CreateProjectFromTemplate(gallery, filename, *template, vars)
+	} else {
+		filename, err = e.tools.CreateProjectWithOptions(positional[0], opts)
+	}
+	if err != nil {
+		return err
+	}
+	path, err := e.tools.Path(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, path)
+	return nil
+}
+
+func runList(e *env, args []string) error {
+	fs := e.newFlagSet()
+	asJSON := fs.Bool("json", false, "print the decks as JSON")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+
+	projects, err := e.tools.ListProjects()
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		if projects == nil {
+			projects = []slidev.Project{}
+		}
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(projects)
+	}
+	for _, p := range projects {
+		fmt.Fprintf(e.stdout, "%s\t%s\n", p.Updated, p.Name)
+	}
+	return nil
+}
+
+func runGenerate(e *env, args []string) error {
+	fs := e.newFlagSet()
+	from := fs.String("from", "", "file with the source notes, - for stdin")
+	styleID := fs.String("style", "", "prompt style ID (the selected style when empty)")
+	theme := fs.String("theme", "default", "Slidev theme of the deck")
+	out := fs.String("out", "", "deck to write (named after the notes file when empty)")
+	outlinePath := fs.String("outline", "", "also write the generated outline JSON to this file")
+	notes := fs.Bool("notes", config.Get().Generation.SpeakerNotes, "draft speaker notes for the slides")
+	fixRounds := fs.Int("fix-rounds", config.Get().Generation.LintFixRounds(), "times slides with lint problems go back to the model, 0 to skip linting")
+	force := fs.Bool("force", false, "overwrite the deck if it already exists")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *from == "" || len(positional) != 0 {
+		return errUsage
+	}
+
+	var text []byte
+	if *from == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(*from)
+	}
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(text)) == "" {
+		return fmt.Errorf("no notes to generate from")
+	}
+
+	filename := *out
+	if filename == "" {
+		if *from == "-" {
+			return fmt.Errorf("-out is required when reading notes from stdin")
+		}
+		filename = strings.TrimSuffix(filepath.Base(*from), filepath.Ext(*from))
+	}
+	filename = deckFile(filename)
+	// Check the name before spending tokens on a deck that cannot be saved
+	path, err := e.tools.Path(filename)
+	if err != nil {
+		return err
+	}
+	if !*force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%w: %s (use -force to overwrite)", slidev.ErrProjectExists, filename)
+		}
+	}
+
+	style := config.SelectedStyle()
+	if *styleID != "" {
+		if style, err = config.GetStyle(*styleID); err != nil {
+			return err
+		}
+	}
+
+	pipeline := ai.NewPipeline(style, *theme)
+	pipeline.Notes = *notes
+	pipeline.FixRounds = *fixRounds
+	pipeline.OnStage = func(stage config.Stage) {
+		fmt.Fprintf(e.stderr, "%s...\n", stage)
+	}
+	result, err := pipeline.Run(e.ctx, string(text))
+	if err != nil {
+		return err
+	}
+
+	if *outlinePath != "" {
+		data, err := json.MarshalIndent(result.Outline, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*outlinePath, data, 0644); err != nil {
+			return err
+		}
+	}
+	if err := e.tools.SaveSlides(filename, result.Slides); err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, path)
+	return nil
+}
+
+func runExport(e *env, args []string) error {
+	fs := e.newFlagSet()
+	var opts slidev.ExportOptions
+	fs.StringVar(&opts.Format, "format", "pdf", "output format: "+strings.Join(slidev.ExportFormats, ", "))
+	fs.StringVar(&opts.Output, "output", "", "output path, relative to the workspace (named after the deck when empty)")
+	fs.BoolVar(&opts.WithClicks, "with-clicks", false, "export each click step as a page")
+	fs.StringVar(&opts.Range, "range", "", "slides to export, e.g. 1,3-5")
+	fs.BoolVar(&opts.Dark, "dark", false, "export in dark mode")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	path, err := slidev.Export(e.ctx, e.tools.Dir(), deckFile(positional[0]), opts, e.stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, path)
+	return nil
+}
+
+func runServe(e *env, args []string) error {
+	fs := e.newFlagSet()
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return errUsage
+	}
+	filename := "slides.md"
+	if len(positional) == 1 {
+		filename = deckFile(positional[0])
+	}
+	path, err := e.tools.Path(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	server := slidev.NewServer()
+	server.Logs = e.stderr // stdout carries only the URL
+	url, err := server.Start(e.tools.Dir(), filename)
+	if err != nil {
+		return err
+	}
+	defer server.Stop()
+
+	fmt.Fprintln(e.stdout, url)
+	fmt.Fprintln(e.stderr, "Press Ctrl+C to stop")
+	<-e.ctx.Done()
+	return nil
+}
+
+func runTheme(e *env, args []string) error {
+	fs := e.newFlagSet()
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 3 || positional[0] != "set" {
+		return errUsage
+	}
+	return e.tools.ApplyGlobalTheme(deckFile(positional[1]), positional[2])
+}
+
+func runAPI(e *env, args []string) error {
+	fs := e.newFlagSet()
+	port := fs.Int("port", config.Get().API.ListenPort(), "port to listen on")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+
+	token, err := httpapi.LoadToken(config.Dir())
+	if err != nil {
+		return err
+	}
+	slidevServer := slidev.NewServer()
+	slidevServer.Logs = e.stderr // stdout carries only the URL
+	defer slidevServer.Stop()
+	api := httpapi.NewServer(e.tools, slidevServer, token)
+	api.MCP = mcp.NewServer(e.tools, Version)
+	url, err := api.Start(*port)
+	if err != nil {
+		return err
+	}
+	defer api.Stop()
+
+	fmt.Fprintln(e.stdout, url)
+	fmt.Fprintf(e.stderr, "Token in %s\nPress Ctrl+C to stop\n", filepath.Join(config.Dir(), httpapi.TokenFile))
+	<-e.ctx.Done()
+	return nil
+}
+
+func runMCP(e *env, args []string) error {
+	fs := e.newFlagSet()
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+	// stdout carries the protocol, so nothing else may be printed there
+	return mcp.NewServer(e.tools, Version).ServeStdio(e.ctx, os.Stdin, e.stdout)
+}
+
+func runTemplates(e *env, args []string) error {
+	fs := e.newFlagSet()
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+	templates, err := slidev.NewTemplates(templatesDir()).List()
+	if err != nil {
+		return err
+	}
+	for _, tpl := range templates {
+		fmt.Fprintf(e.stdout, "%s\t%s\t%s\n", tpl.ID, tpl.Name, tpl.Description)
+	}
+	return nil
+}
+
+// templatesDir is where the app keeps saved templates
+func templatesDir() string {
+	return filepath.Join(config.Dir(), "templates")
+}
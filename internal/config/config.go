@@ -1,11 +1,20 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
+// AppName names the per-user directories the application stores data in
+const AppName = "slidev-studio-ai"
+
 type AIConfig struct {
 	Provider string `json:"provider"` // "openai", "openai-compatible", "google", "anthropic"
 	APIKey   string `json:"apiKey"`
@@ -31,58 +40,296 @@ type PromptConfig struct {
 }
 
 type Config struct {
-	AI      AIConfig     `json:"ai"`
-	Prompts PromptConfig `json:"prompts"`
+	Version    int              `json:"version"`
+	Workspace  string           `json:"workspace,omitempty"` // Directory containing the decks; the working directory when empty
+	AI         AIConfig         `json:"ai"`                  // Mirrors the active provider profile
+	Providers  ProviderConfig   `json:"providers"`
+	Prompts    PromptConfig     `json:"prompts"`
+	Updates    UpdateConfig     `json:"updates"`
+	API        APIConfig        `json:"api"`
+	Trash      TrashConfig      `json:"trash"`
+	Editor     EditorConfig     `json:"editor"`
+	Generation GenerationConfig `json:"generation"`
+}
+
+// UpdateConfig controls where and how application updates are looked up
+type UpdateConfig struct {
+	Channel          string `json:"channel"`                    // "stable" (default) or "beta"
+	Repository       string `json:"repository"`                 // GitHub "owner/repo"; the official repository when empty
+	DisableAutoCheck bool   `json:"disableAutoCheck,omitempty"` // Turns off the periodic background check
+	SkippedVersion   string `json:"skippedVersion,omitempty"`   // Release the user chose not to be told about
+}
+
+// APIConfig controls the local HTTP API other tools use to drive decks
+type APIConfig struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port,omitempty"` // DefaultAPIPort when 0
+}
+
+// DefaultAPIPort is the port the local HTTP API listens on unless configured
+const DefaultAPIPort = 17321
+
+// ListenPort returns the effective port of the local HTTP API
+func (c APIConfig) ListenPort() int {
+	if c.Port == 0 {
+		return DefaultAPIPort
+	}
+	return c.Port
+}
+
+// TrashConfig controls how long deleted decks are kept
+type TrashConfig struct {
+	RetentionDays int `json:"retentionDays,omitempty"` // DefaultTrashRetentionDays when 0
+}
+
+// DefaultTrashRetentionDays is how long deleted decks stay in the trash
+// unless configured
+const DefaultTrashRetentionDays = 30
+
+// Retention returns how long deleted decks are kept before being purged
+func (c TrashConfig) Retention() time.Duration {
+	days := c.RetentionDays
+	if days == 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// EditorConfig controls how the app edits decks
+type EditorConfig struct {
+	FormatOnSave bool `json:"formatOnSave,omitempty"` // Format decks with the canonical formatter whenever they are saved
+}
+
+// GenerationConfig controls the optional steps of deck generation, in the
+// app and in the generate command alike
+type GenerationConfig struct {
+	SpeakerNotes bool `json:"speakerNotes,omitempty"` // Draft speaker notes for generated slides
+	// FixRounds is how often slides the linter finds problems in are sent
+	// back to the model; DefaultFixRounds when 0, none when negative
+	FixRounds int `json:"fixRounds,omitempty"`
+}
+
+// DefaultFixRounds is how often generated slides with lint problems are sent
+// back to the model unless configured
+const DefaultFixRounds = 2
+
+// LintFixRounds returns the effective number of lint fix rounds
+func (c GenerationConfig) LintFixRounds() int {
+	switch {
+	case c.FixRounds == 0:
+		return DefaultFixRounds
+	case c.FixRounds < 0:
+		return 0
+	}
+	return c.FixRounds
+}
+
+// SetGeneration saves the deck generation options
+func SetGeneration(g GenerationConfig) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	cfg := fileConfig
+	cfg.Generation = g
+	return saveLocked(cfg)
 }
 
 var (
-	currentConfig Config
+	currentConfig Config // Effective config: the file layer plus env/flag overrides
+	fileConfig    Config // What is persisted in the config file
+	origins       map[string]Source
 	mutex         sync.RWMutex
-	configPath    = "config.json"
+	configPath    = defaultConfigPath()
 )
 
+// legacyConfigPath is where versions before the per-user config dir kept
+// their config, relative to the working directory
+const legacyConfigPath = "config.json"
+
+// defaultConfigPath returns config.json in the OS user config dir, falling
+// back to the working directory when there is none
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return legacyConfigPath
+	}
+	return filepath.Join(dir, AppName, "config.json")
+}
+
+// Path returns the location of the config file
+func Path() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return configPath
+}
+
+// Dir returns the per-user directory holding the config file and other
+// application data
+func Dir() string {
+	return filepath.Dir(Path())
+}
+
 func Load() (*Config, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	data, err := os.ReadFile(configPath)
+	if p := pathOverride(); p != "" {
+		configPath = p
+	}
+
+	data, fromLegacy, err := readConfigFile()
 	if os.IsNotExist(err) {
-		// Default config
-		currentConfig = Config{
-			AI: AIConfig{
-				Provider: "ollama",
-				BaseURL:  "http://localhost:11434/v1",
-				Model:    "llama3",
-			},
+		cfg := defaultConfig()
+		syncActiveProvider(&cfg, nil)
+		if err := writeLocked(cfg); err != nil {
+			return &currentConfig, err
 		}
-		return &currentConfig, nil
+		return &currentConfig, applyOverridesLocked()
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, &currentConfig); err != nil {
+	migrated, changed, err := migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
 		return nil, err
 	}
+	migrateKeys := hasPlaintextKeys(cfg)
+	secretErr := resolveSecrets(&cfg)
+	syncActiveProvider(&cfg, nil)
+	fileConfig = cfg
+
+	// Persist migrations, including moving API keys left in clear text by
+	// older versions into the secret store
+	if fromLegacy || migrateKeys || changed {
+		if err := writeLocked(cfg); err != nil {
+			return &currentConfig, err
+		}
+	}
+	if fromLegacy {
+		// Kept rather than deleted, in case an older version is run again
+		_ = os.Rename(legacyConfigPath, legacyConfigPath+".migrated")
+	}
+
+	return &currentConfig, errors.Join(secretErr, applyOverridesLocked())
+}
+
+// readConfigFile reads the config file, importing the legacy working
+// directory config when the per-user one does not exist yet. The CLI runs in
+// arbitrary folders, so a config.json that is not this app's is ignored.
+func readConfigFile() (data []byte, fromLegacy bool, err error) {
+	data, err = os.ReadFile(configPath)
+	if !os.IsNotExist(err) || configPath == legacyConfigPath {
+		return data, false, err
+	}
+	if legacy, lerr := os.ReadFile(legacyConfigPath); lerr == nil && isAppConfig(legacy) {
+		return legacy, true, nil
+	}
+	return nil, false, err
+}
+
+// isAppConfig reports whether data looks like a config file of this app: a
+// JSON object with "ai" or "prompts" and no keys Config does not define
+func isAppConfig(data []byte) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	_, hasAI := fields["ai"]
+	_, hasPrompts := fields["prompts"]
+	if !hasAI && !hasPrompts {
+		return false
+	}
+	known := make(map[string]bool)
+	typ := reflect.TypeOf(Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+	}
+	for key := range fields {
+		if !known[key] {
+			return false
+		}
+	}
+	return true
+}
 
-	return &currentConfig, nil
+func defaultConfig() Config {
+	return Config{
+		Version: CurrentVersion,
+		AI: AIConfig{
+			Provider: "ollama",
+			BaseURL:  "http://localhost:11434/v1",
+			Model:    "llama3",
+		},
+	}
 }
 
 func Save(cfg Config) error {
 	mutex.Lock()
 	defer mutex.Unlock()
+	prev := currentConfig
+	syncActiveProvider(&cfg, &prev)
+	if err := validateEditedStyles(cfg.Prompts.CustomStyles, fileConfig.Prompts.CustomStyles); err != nil {
+		return err
+	}
+	return saveLocked(withoutOverrides(cfg, prev))
+}
+
+// saveLocked validates the file layer cfg, persists it and makes it current.
+// The caller must hold mutex.
+func saveLocked(cfg Config) error {
+	if err := Validate(cfg); err != nil {
+		return err
+	}
+	return writeLocked(cfg)
+}
 
-	currentConfig = cfg
-	data, err := json.MarshalIndent(cfg, "", "  ")
+// writeLocked persists the file layer cfg and makes it current. API keys are moved to the
+// secret store; the file only keeps references. The previous file is kept as
+// a backup. The caller must hold mutex.
+func writeLocked(cfg Config) error {
+	cfg.Version = CurrentVersion
+	disk, err := storeSecrets(&cfg, fileConfig)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(disk, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return err
+	}
+	if prev, err := os.ReadFile(configPath); err == nil && !bytes.Equal(prev, data) {
+		if err := writeBackup(prev); err != nil {
+			return err
+		}
+	}
+
+	tmp := configPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, configPath); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	fileConfig = cfg
+	_ = applyOverridesLocked() // Override errors were already reported by Load
+	return nil
 }
 
 func Get() Config {
 	mutex.RLock()
 	defer mutex.RUnlock()
-	return currentConfig
+	// Copies, so that editing the result leaves the stored config alone
+	cfg := currentConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	cfg.Prompts.CustomStyles = cloneStyles(cfg.Prompts.CustomStyles)
+	return cfg
 }
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"slidev-studio-ai/internal/secrets"
+)
+
+func TestLoadMigratesAndValidates(t *testing.T) {
+	configPath = filepath.Join(useTempConfig(t), "nested", "config.json")
+
+	// A missing config is created with defaults
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Version != CurrentVersion || cfg.AI.Provider != "ollama" {
+		t.Errorf("unexpected default config: %+v", cfg)
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		t.Fatalf("expected defaults to be persisted: %v", err)
+	}
+
+	// A version 0 config is upgraded and the previous file kept as a backup
+	legacy := `{"ai": {"provider": "anthropic", "model": "claude"}}`
+	if err := os.WriteFile(configPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Providers.Profiles) != 1 || cfg.Providers.Profiles[0].Provider != "anthropic" {
+		t.Errorf("expected migrated profile, got %+v", cfg.Providers)
+	}
+	var onDisk map[string]any
+	data, _ := os.ReadFile(configPath)
+	if err := json.Unmarshal(data, &onDisk); err != nil || onDisk["version"] != float64(CurrentVersion) {
+		t.Errorf("expected version %d on disk, got %v (%v)", CurrentVersion, onDisk["version"], err)
+	}
+	if backup, err := os.ReadFile(configPath + ".bak"); err != nil || !strings.Contains(string(backup), "claude") {
+		t.Errorf("expected backup of previous file, got %q (%v)", backup, err)
+	}
+
+	// Invalid values are reported per field and nothing is written
+	bad := Get()
+	bad.Providers.Profiles[0].BaseURL = "localhost:11434"
+	bad.Providers.Profiles[0].Provider = "skynet"
+	err = Save(bad)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Fatalf("expected 2 field errors, got %v", err)
+	}
+	if verr.Errors[0].Field != "providers.profiles[0].provider" || verr.Errors[1].Field != "providers.profiles[0].baseUrl" {
+		t.Errorf("unexpected fields: %+v", verr.Errors)
+	}
+	if Get().AI.Provider != "anthropic" {
+		t.Errorf("invalid config must not become current")
+	}
+}
+
+// useTempConfig points the package at an empty config in a temporary
+// directory and returns that directory
+func useTempConfig(t *testing.T) string {
+	t.Helper()
+	t.Setenv(secrets.BackendEnv, "file")
+	dir := t.TempDir()
+	configPath = filepath.Join(dir, "config.json")
+	currentConfig, fileConfig, origins = Config{}, Config{}, nil
+	unresolvedRefs = map[string]bool{}
+	return dir
+}
+
+func TestOverrideLayers(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(Config{AI: AIConfig{Provider: "openai", Model: "gpt-4o", BaseURL: "https://api.openai.com/v1"}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	t.Setenv("SLIDEV_AI_BASE_URL", "https://ci.example.com/v1")
+	t.Setenv("SLIDEV_AI_MODEL", "env-model")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs)
+	defer func() { boundFlags = nil }()
+	if err := fs.Parse([]string{"--model", "flag-model"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AI.BaseURL != "https://ci.example.com/v1" || cfg.AI.Model != "flag-model" {
+		t.Errorf("overrides not applied: %+v", cfg.AI)
+	}
+	for field, want := range map[string]Source{
+		"ai.baseUrl":  SourceEnv,
+		"ai.model":    SourceFlag,
+		"ai.provider": SourceFile,
+		"workspace":   SourceDefault,
+	} {
+		if got := Origin(field); got != want {
+			t.Errorf("Origin(%s) = %s, want %s", field, got, want)
+		}
+	}
+
+	// Saving the effective config does not persist override values
+	edited := Get()
+	edited.Prompts.SelectedStyleID = "tech"
+	if err := Save(edited); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "ci.example.com") || strings.Contains(string(data), "flag-model") {
+		t.Errorf("override values written to config file: %s", data)
+	}
+	if Get().AI.Model != "flag-model" {
+		t.Errorf("expected override to stay effective after save, got %s", Get().AI.Model)
+	}
+}
+
+func TestProfileOverridesStayOutOfFile(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(Config{AI: AIConfig{Provider: "ollama", Model: "llama3"}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := SaveProfile(ProviderProfile{ID: "cloud", Name: "Cloud", Provider: "openai", APIKey: "sk-file", Model: "gpt-4o", BaseURL: "https://api.openai.com/v1"}); err != nil {
+		t.Fatalf("SaveProfile failed: %v", err)
+	}
+
+	// The ai.* values apply to the profile selected by the override, not the
+	// one active in the file
+	t.Setenv("SLIDEV_AI_PROFILE", "cloud")
+	t.Setenv("SLIDEV_AI_MODEL", "env-model")
+	t.Setenv("SLIDEV_AI_API_KEY", "sk-env")
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if p := ActiveProfile(); p.ID != "cloud" || p.Model != "env-model" || p.APIKey != "sk-env" {
+		t.Fatalf("overrides not applied: %+v", p)
+	}
+
+	// An unrelated save and a profile switch keep the cloud profile's file
+	// values
+	edited := Get()
+	edited.Prompts.SelectedStyleID = "tech"
+	if err := Save(edited); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	edited = Get()
+	edited.Providers.ActiveID = "default"
+	if err := Save(edited); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "env-model") || strings.Contains(string(data), `"activeId": "cloud"`) {
+		t.Errorf("override values written to config file: %s", data)
+	}
+
+	os.Unsetenv("SLIDEV_AI_PROFILE")
+	os.Unsetenv("SLIDEV_AI_MODEL")
+	os.Unsetenv("SLIDEV_AI_API_KEY")
+	currentConfig, fileConfig = Config{}, Config{}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg := Get()
+	cloud := cfg.Providers.Profiles[findProfile(cfg.Providers.Profiles, "cloud")]
+	if cloud.Model != "gpt-4o" || cloud.APIKey != "sk-file" {
+		t.Errorf("expected cloud profile file values, got %+v", cloud)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(Config{AI: AIConfig{Provider: "ollama", Model: "llama3"}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	events := make(chan ChangeEvent, 10)
+	unsubscribe := Subscribe(func(ev ChangeEvent) {
+		if ev.New.Prompts.SelectedStyleID == "tech" {
+			events <- ev
+		}
+	})
+	defer unsubscribe()
+
+	if err := SelectStyle("tech"); err != nil {
+		t.Fatalf("SelectStyle failed: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if !ev.Changed(SectionPrompts) || ev.Changed(SectionAI) {
+			t.Errorf("unexpected sections: %v", ev.Sections)
+		}
+		if ev.Old.Prompts.SelectedStyleID == "tech" {
+			t.Errorf("expected old value in event, got %+v", ev.Old.Prompts)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no change event received")
+	}
+}
+
+func TestLegacyConfigImport(t *testing.T) {
+	dir := useTempConfig(t)
+	configPath = filepath.Join(dir, "user", "config.json")
+	project := t.TempDir()
+	t.Chdir(project)
+
+	// A config.json of some other tool is left alone
+	foreign := `{"ai": {"model": "x"}, "compilerOptions": {"strict": true}}`
+	if err := os.WriteFile(legacyConfigPath, []byte(foreign), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AI.Model == "x" {
+		t.Errorf("foreign config.json was imported: %+v", cfg.AI)
+	}
+	if data, err := os.ReadFile(legacyConfigPath); err != nil || string(data) != foreign {
+		t.Errorf("foreign config.json was changed: %q (%v)", data, err)
+	}
+
+	// This app's legacy config is imported and kept under another name
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"ai": {"provider": "anthropic", "model": "claude"}, "prompts": {"selectedStyleId": "tech"}}`
+	if err := os.WriteFile(legacyConfigPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AI.Model != "claude" || cfg.Prompts.SelectedStyleID != "tech" {
+		t.Errorf("legacy config not imported: %+v", cfg)
+	}
+	if _, err := os.Stat(legacyConfigPath); !os.IsNotExist(err) {
+		t.Errorf("legacy config.json still in place: %v", err)
+	}
+	if data, err := os.ReadFile(legacyConfigPath + ".migrated"); err != nil || string(data) != legacy {
+		t.Errorf("legacy config not kept as .migrated: %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Section names a top-level part of the config
+type Section string
+
+const (
+	SectionWorkspace  Section = "workspace"
+	SectionAI         Section = "ai"
+	SectionProviders  Section = "providers"
+	SectionPrompts    Section = "prompts"
+	SectionUpdates    Section = "updates"
+	SectionAPI        Section = "api"
+	SectionTrash      Section = "trash"
+	SectionEditor     Section = "editor"
+	SectionGeneration Section = "generation"
+)
+
+// ChangeEvent describes an update of the effective config
+type ChangeEvent struct {
+	Sections []Section `json:"sections"` // Sections whose values changed
+	Old      Config    `json:"old"`
+	New      Config    `json:"new"`
+}
+
+// Changed reports whether the given section changed
+func (e ChangeEvent) Changed(section Section) bool {
+	for _, s := range e.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Listener is called for every config change, in order, on a dedicated
+// goroutine. Listeners may call back into the config package.
+type Listener func(ChangeEvent)
+
+var (
+	listenersMu  sync.Mutex
+	listeners    = map[int]Listener{}
+	nextListener int
+
+	queueMu    sync.Mutex
+	queueCond  = sync.NewCond(&queueMu)
+	queue      []ChangeEvent
+	dispatchGo sync.Once
+)
+
+// Subscribe registers fn to be told about config changes and returns a
+// function that removes it
+func Subscribe(fn Listener) (unsubscribe func()) {
+	listenersMu.Lock()
+	id := nextListener
+	nextListener++
+	listeners[id] = fn
+	listenersMu.Unlock()
+
+	return func() {
+		listenersMu.Lock()
+		delete(listeners, id)
+		listenersMu.Unlock()
+	}
+}
+
+// diffSections returns the sections that differ between two configs
+func diffSections(old, new Config) []Section {
+	var sections []Section
+	if old.Workspace != new.Workspace {
+		sections = append(sections, SectionWorkspace)
+	}
+	if old.AI != new.AI {
+		sections = append(sections, SectionAI)
+	}
+	if !reflect.DeepEqual(old.Providers, new.Providers) {
+		sections = append(sections, SectionProviders)
+	}
+	if !reflect.DeepEqual(old.Prompts, new.Prompts) {
+		sections = append(sections, SectionPrompts)
+	}
+	if old.Updates != new.Updates {
+		sections = append(sections, SectionUpdates)
+	}
+	if old.API != new.API {
+		sections = append(sections, SectionAPI)
+	}
+	if old.Trash != new.Trash {
+		sections = append(sections, SectionTrash)
+	}
+	if old.Editor != new.Editor {
+		sections = append(sections, SectionEditor)
+	}
+	if old.Generation != new.Generation {
+		sections = append(sections, SectionGeneration)
+	}
+	return sections
+}
+
+// publish queues a change event for listeners. It never blocks, so it is
+// safe to call while holding mutex.
+func publish(old, new Config) {
+	sections := diffSections(old, new)
+	if len(sections) == 0 {
+		return
+	}
+
+	dispatchGo.Do(func() { go dispatch() })
+	queueMu.Lock()
+	queue = append(queue, ChangeEvent{Sections: sections, Old: old, New: new})
+	queueMu.Unlock()
+	queueCond.Signal()
+}
+
+func dispatch() {
+	for {
+		queueMu.Lock()
+		for len(queue) == 0 {
+			queueCond.Wait()
+		}
+		ev := queue[0]
+		queue = queue[1:]
+		queueMu.Unlock()
+
+		listenersMu.Lock()
+		fns := make([]Listener, 0, len(listeners))
+		for id := 0; id < nextListener; id++ {
+			if fn, ok := listeners[id]; ok {
+				fns = append(fns, fn)
+			}
+		}
+		listenersMu.Unlock()
+
+		for _, fn := range fns {
+			fn(ev)
+		}
+	}
+}
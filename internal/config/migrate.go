@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the config schema version written by this build
+const CurrentVersion = 1
+
+// migration upgrades a raw config document from version `from` to from+1
+type migration struct {
+	from  int
+	name  string
+	apply func(raw map[string]any) error
+}
+
+// migrations is the ordered upgrade chain. Configs without a version field
+// are version 0.
+var migrations = []migration{
+	{from: 0, name: "move ai settings into provider profiles", apply: migrateProviderProfiles},
+}
+
+// migrate upgrades a config document to CurrentVersion, reporting whether
+// anything changed
+func migrate(data []byte) ([]byte, bool, error) {
+	raw := map[string]any{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, err
+	}
+
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > CurrentVersion {
+		return nil, false, fmt.Errorf("config version %d is newer than supported version %d", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, false, nil
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if m.from != version {
+			return nil, false, fmt.Errorf("no config migration from version %d", version)
+		}
+		if err := m.apply(raw); err != nil {
+			return nil, false, fmt.Errorf("config migration %d (%s): %w", m.from, m.name, err)
+		}
+		version++
+	}
+	raw["version"] = version
+
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+// migrateProviderProfiles turns the single "ai" section into the "default"
+// provider profile
+func migrateProviderProfiles(raw map[string]any) error {
+	if _, ok := raw["providers"]; ok {
+		return nil
+	}
+	ai, _ := raw["ai"].(map[string]any)
+	if ai == nil {
+		return nil
+	}
+
+	profile := map[string]any{"id": "default", "name": "Default"}
+	for _, key := range []string{"provider", "apiKey", "baseUrl", "model"} {
+		if v, ok := ai[key]; ok {
+			profile[key] = v
+		}
+	}
+	raw["providers"] = map[string]any{
+		"profiles": []any{profile},
+		"activeId": "default",
+	}
+	return nil
+}
+
+// writeBackup keeps the previous config file next to the current one. API
+// keys are scrubbed so a backup never undoes moving them to the secret store.
+func writeBackup(prev []byte) error {
+	var raw any
+	if err := json.Unmarshal(prev, &raw); err == nil {
+		scrubAPIKeys(raw)
+		if scrubbed, err := json.MarshalIndent(raw, "", "  "); err == nil {
+			prev = scrubbed
+		}
+	}
+	return os.WriteFile(configPath+".bak", prev, 0600)
+}
+
+func scrubAPIKeys(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if k == "apiKey" {
+				delete(v, k)
+				continue
+			}
+			scrubAPIKeys(child)
+		}
+	case []any:
+		for _, child := range v {
+			scrubAPIKeys(child)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Source identifies the layer an effective config value came from. Layers
+// are applied in order: defaults < file < env vars < flags.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+const (
+	configPathEnv  = "SLIDEV_AI_CONFIG"
+	configPathFlag = "config"
+)
+
+// override is a config value that can be set from the environment or the
+// command line without touching the config file
+type override struct {
+	field string // JSON path of the value
+	env   string
+	flag  string
+	usage string
+	get   func(cfg *Config) string
+	set   func(cfg *Config, value string) error
+
+	profile func(p *ProviderProfile) *string // The value in a profile, for ai.* overrides
+}
+
+// overrides are applied in order; the profile is selected before the ai.*
+// values are applied to it
+var overrides = []override{
+	{
+		field: "workspace", env: "SLIDEV_AI_WORKSPACE", flag: "workspace",
+		usage: "directory containing the decks",
+		get:   func(cfg *Config) string { return cfg.Workspace },
+		set: func(cfg *Config, v string) error {
+			cfg.Workspace = v
+			return nil
+		},
+	},
+	{
+		field: "providers.activeId", env: "SLIDEV_AI_PROFILE", flag: "profile",
+		usage: "AI provider profile to use",
+		get:   func(cfg *Config) string { return cfg.Providers.ActiveID },
+		set: func(cfg *Config, v string) error {
+			if findProfile(cfg.Providers.Profiles, v) < 0 {
+				return fmt.Errorf("%w: %s", ErrProfileNotFound, v)
+			}
+			cfg.Providers.ActiveID = v
+			syncActiveProvider(cfg, nil)
+			return nil
+		},
+	},
+	aiOverride("ai.provider", "SLIDEV_AI_PROVIDER", "provider", "AI provider type",
+		func(p *ProviderProfile) *string { return &p.Provider }),
+	aiOverride("ai.apiKey", "SLIDEV_AI_API_KEY", "api-key", "AI provider API key",
+		func(p *ProviderProfile) *string { return &p.APIKey }),
+	aiOverride("ai.baseUrl", "SLIDEV_AI_BASE_URL", "base-url", "AI provider base URL",
+		func(p *ProviderProfile) *string { return &p.BaseURL }),
+	aiOverride("ai.model", "SLIDEV_AI_MODEL", "model", "AI model name",
+		func(p *ProviderProfile) *string { return &p.Model }),
+	{
+		field: "prompts.selectedStyleId", env: "SLIDEV_AI_STYLE", flag: "style",
+		usage: "prompt style used for generation",
+		get:   func(cfg *Config) string { return cfg.Prompts.SelectedStyleID },
+		set: func(cfg *Config, v string) error {
+			if findStyle(mergeStyles(cfg.Prompts.CustomStyles), v) < 0 {
+				return fmt.Errorf("%w: %s", ErrStyleNotFound, v)
+			}
+			cfg.Prompts.SelectedStyleID = v
+			return nil
+		},
+	},
+}
+
+// aiOverride builds an override for a value of the active provider profile
+func aiOverride(field, env, flagName, usage string, value func(p *ProviderProfile) *string) override {
+	return override{
+		field: field, env: env, flag: flagName, usage: usage, profile: value,
+		get: func(cfg *Config) string {
+			if i := findProfile(cfg.Providers.Profiles, cfg.Providers.ActiveID); i >= 0 {
+				return *value(&cfg.Providers.Profiles[i])
+			}
+			return ""
+		},
+		set: func(cfg *Config, v string) error {
+			syncActiveProvider(cfg, nil)
+			i := findProfile(cfg.Providers.Profiles, cfg.Providers.ActiveID)
+			*value(&cfg.Providers.Profiles[i]) = v
+			syncActiveProvider(cfg, nil)
+			return nil
+		},
+	}
+}
+
+var boundFlags *flag.FlagSet
+
+// BindFlags registers a command line flag for every overridable value on fs.
+// Values of flags set on the command line take precedence over env vars and
+// the config file once fs has been parsed and Load is called.
+func BindFlags(fs *flag.FlagSet) {
+	fs.String(configPathFlag, "", "path to the config file (env "+configPathEnv+")")
+	for _, o := range overrides {
+		fs.String(o.flag, "", o.usage+" (env "+o.env+")")
+	}
+	mutex.Lock()
+	boundFlags = fs
+	mutex.Unlock()
+}
+
+// flagValue returns the value of a bound flag if it was set on the command line
+func flagValue(name string) (string, bool) {
+	if boundFlags == nil || !boundFlags.Parsed() {
+		return "", false
+	}
+	var value string
+	set := false
+	boundFlags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			value, set = f.Value.String(), true
+		}
+	})
+	return value, set
+}
+
+// lookupOverride returns the highest-precedence override for o, if any
+func lookupOverride(o override) (string, Source, bool) {
+	if v, ok := flagValue(o.flag); ok {
+		return v, SourceFlag, true
+	}
+	if v, ok := os.LookupEnv(o.env); ok {
+		return v, SourceEnv, true
+	}
+	return "", "", false
+}
+
+// pathOverride returns the config file location requested by flag or env
+func pathOverride() string {
+	if v, ok := flagValue(configPathFlag); ok {
+		return v
+	}
+	return os.Getenv(configPathEnv)
+}
+
+// applyOverridesLocked recomputes the effective config from fileConfig and
+// the env/flag overrides. The caller must hold mutex.
+func applyOverridesLocked() error {
+	cfg := fileConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	defaults := defaultConfig()
+	syncActiveProvider(&defaults, nil)
+
+	var errs []error
+	overridden := false
+	origins = make(map[string]Source, len(overrides))
+	for _, o := range overrides {
+		if v, src, ok := lookupOverride(o); ok {
+			if err := o.set(&cfg, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s override: %w", o.field, err))
+			} else {
+				origins[o.field] = src
+				overridden = true
+				continue
+			}
+		}
+		if o.get(&cfg) == o.get(&defaults) {
+			origins[o.field] = SourceDefault
+		} else {
+			origins[o.field] = SourceFile
+		}
+	}
+
+	old := currentConfig
+	currentConfig = cfg
+	publish(old, cfg)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if overridden {
+		if err := Validate(cfg); err != nil {
+			return fmt.Errorf("config overrides: %w", err)
+		}
+	}
+	return nil
+}
+
+// withoutOverrides returns the file layer for an edited effective config:
+// overridden values the caller left unchanged are restored to their file
+// values so env and flag values never end up in the config file
+func withoutOverrides(cfg Config, prev Config) Config {
+	cfg.Providers = cloneProviders(cfg.Providers)
+	for _, o := range overrides {
+		if src := origins[o.field]; src != SourceEnv && src != SourceFlag {
+			continue
+		}
+		if o.profile != nil {
+			restoreProfileValue(&cfg, prev, o.profile)
+		} else if o.get(&cfg) == o.get(&prev) {
+			_ = o.set(&cfg, o.get(&fileConfig))
+		}
+	}
+	syncActiveProvider(&cfg, nil)
+	return cfg
+}
+
+// restoreProfileValue restores the file value of an ai.* override. It was
+// applied to the profile active in prev, which cfg may no longer select, so
+// that profile is compared by ID rather than through the active one.
+func restoreProfileValue(cfg *Config, prev Config, value func(p *ProviderProfile) *string) {
+	id := prev.Providers.ActiveID
+	i := findProfile(cfg.Providers.Profiles, id)
+	j := findProfile(prev.Providers.Profiles, id)
+	k := findProfile(fileConfig.Providers.Profiles, id)
+	if i < 0 || j < 0 || k < 0 {
+		return
+	}
+	if *value(&cfg.Providers.Profiles[i]) == *value(&prev.Providers.Profiles[j]) {
+		*value(&cfg.Providers.Profiles[i]) = *value(&fileConfig.Providers.Profiles[k])
+	}
+}
+
+// Setting describes an effective config value and where it came from
+type Setting struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"` // Secrets are masked
+	Source Source `json:"source"`
+	Env    string `json:"env"`
+	Flag   string `json:"flag"`
+}
+
+// Settings reports the effective value and source of every overridable value
+func Settings() []Setting {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	settings := make([]Setting, 0, len(overrides))
+	for _, o := range overrides {
+		value := o.get(&currentConfig)
+		if o.field == "ai.apiKey" && value != "" {
+			value = "********"
+		}
+		settings = append(settings, Setting{
+			Field:  o.field,
+			Value:  value,
+			Source: origins[o.field],
+			Env:    o.env,
+			Flag:   "--" + o.flag,
+		})
+	}
+	return settings
+}
+
+// Origin reports which layer the effective value of field came from
+func Origin(field string) Source {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if src, ok := origins[field]; ok {
+		return src
+	}
+	return SourceDefault
+}
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stage identifies a step of the AI generation pipeline
+type Stage string
+
+const (
+	StagePreprocess Stage = "preprocess"
+	StageOutline    Stage = "outline"
+	StageSlides     Stage = "slides"
+	StageNotes      Stage = "notes"
+	StageChat       Stage = "chat"
+)
+
+// Stages lists every pipeline stage that can be bound to a provider profile
+var Stages = []Stage{StagePreprocess, StageOutline, StageSlides, StageNotes, StageChat}
+
+// DefaultTimeout is the request timeout used when a profile does not set one
+const DefaultTimeout = 300 * time.Second
+
+var ErrProfileNotFound = errors.New("provider profile not found")
+
+// ProviderProfile is a named AI provider configuration
+type ProviderProfile struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Provider    string  `json:"provider"`            // "openai", "openai-compatible", "google", "anthropic", "ollama"
+	APIKey      string  `json:"apiKey,omitempty"`    // Only held in memory; stored in the secret store
+	APIKeyRef   string  `json:"apiKeyRef,omitempty"` // Secret store entry holding the API key
+	BaseURL     string  `json:"baseUrl"`
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"` // 0 leaves the provider default
+	MaxTokens   int     `json:"maxTokens"`   // 0 leaves the provider default
+	Timeout     int     `json:"timeout"`     // Seconds, 0 uses DefaultTimeout
+}
+
+// ProviderConfig stores the provider profiles and which one each stage uses
+type ProviderConfig struct {
+	Profiles []ProviderProfile `json:"profiles"`
+	ActiveID string            `json:"activeId"`         // Profile used by stages without an override
+	Stages   map[Stage]string  `json:"stages,omitempty"` // Optional per-stage profile IDs
+}
+
+// AIConfig returns the connection settings of the profile
+func (p ProviderProfile) AIConfig() AIConfig {
+	return AIConfig{
+		Provider: p.Provider,
+		APIKey:   p.APIKey,
+		BaseURL:  p.BaseURL,
+		Model:    p.Model,
+	}
+}
+
+// RequestTimeout returns the effective request timeout of the profile
+func (p ProviderProfile) RequestTimeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(p.Timeout) * time.Second
+}
+
+func validStage(stage Stage) bool {
+	for _, s := range Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+func findProfile(profiles []ProviderProfile, id string) int {
+	for i, p := range profiles {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func cloneProviders(pc ProviderConfig) ProviderConfig {
+	pc.Profiles = append([]ProviderProfile(nil), pc.Profiles...)
+	stages := make(map[Stage]string, len(pc.Stages))
+	for k, v := range pc.Stages {
+		stages[k] = v
+	}
+	pc.Stages = stages
+	return pc
+}
+
+// syncActiveProvider keeps cfg.AI and the active profile in step. Configs
+// written before profiles existed get a "default" profile built from cfg.AI.
+// When prev is given and cfg.AI was edited directly, the edit is applied to
+// the active profile so older clients that only know about AI keep working.
+func syncActiveProvider(cfg *Config, prev *Config) {
+	cfg.Providers = cloneProviders(cfg.Providers)
+	pc := &cfg.Providers
+
+	if len(pc.Profiles) == 0 {
+		pc.Profiles = []ProviderProfile{{
+			ID:       "default",
+			Name:     "Default",
+			Provider: cfg.AI.Provider,
+			APIKey:   cfg.AI.APIKey,
+			BaseURL:  cfg.AI.BaseURL,
+			Model:    cfg.AI.Model,
+		}}
+	}
+	active := findProfile(pc.Profiles, pc.ActiveID)
+	if active < 0 {
+		active = 0
+		pc.ActiveID = pc.Profiles[0].ID
+	}
+
+	if prev != nil && cfg.AI != prev.AI {
+		p := &pc.Profiles[active]
+		p.Provider = cfg.AI.Provider
+		p.APIKey = cfg.AI.APIKey
+		p.BaseURL = cfg.AI.BaseURL
+		p.Model = cfg.AI.Model
+	}
+	cfg.AI = pc.Profiles[active].AIConfig()
+}
+
+// ListProfiles returns all provider profiles
+func ListProfiles() []ProviderProfile {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return append([]ProviderProfile(nil), currentConfig.Providers.Profiles...)
+}
+
+// GetProfile returns a provider profile by ID
+func GetProfile(id string) (ProviderProfile, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	profiles := currentConfig.Providers.Profiles
+	if i := findProfile(profiles, id); i >= 0 {
+		return profiles[i], nil
+	}
+	return ProviderProfile{}, fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+}
+
+// ActiveProfile returns the profile used by stages without an override
+func ActiveProfile() ProviderProfile {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	profiles := currentConfig.Providers.Profiles
+	if i := findProfile(profiles, currentConfig.Providers.ActiveID); i >= 0 {
+		return profiles[i]
+	}
+	if len(profiles) > 0 {
+		return profiles[0]
+	}
+	return ProviderProfile{Provider: currentConfig.AI.Provider, APIKey: currentConfig.AI.APIKey, BaseURL: currentConfig.AI.BaseURL, Model: currentConfig.AI.Model}
+}
+
+// ProfileForStage returns the profile bound to a pipeline stage, falling
+// back to the active profile
+func ProfileForStage(stage Stage) (ProviderProfile, error) {
+	if !validStage(stage) {
+		return ProviderProfile{}, fmt.Errorf("unknown stage %q", stage)
+	}
+	mutex.RLock()
+	id := currentConfig.Providers.Stages[stage]
+	mutex.RUnlock()
+
+	if id != "" {
+		if p, err := GetProfile(id); err == nil {
+			return p, nil
+		}
+	}
+	return ActiveProfile(), nil
+}
+
+// SaveProfile creates or updates a provider profile. An ID is derived from
+// the name when none is given.
+func SaveProfile(profile ProviderProfile) (ProviderProfile, error) {
+	if strings.TrimSpace(profile.Name) == "" {
+		return ProviderProfile{}, fmt.Errorf("profile name is required")
+	}
+	if strings.TrimSpace(profile.Provider) == "" {
+		return ProviderProfile{}, fmt.Errorf("profile provider is required")
+	}
+	if profile.Temperature < 0 || profile.Temperature > 2 {
+		return ProviderProfile{}, fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if profile.MaxTokens < 0 || profile.Timeout < 0 {
+		return ProviderProfile{}, fmt.Errorf("max tokens and timeout must not be negative")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	cfg := fileConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	if profile.ID == "" {
+		profile.ID = newProfileID(cfg.Providers.Profiles, profile.Name)
+	}
+	if i := findProfile(cfg.Providers.Profiles, profile.ID); i >= 0 {
+		cfg.Providers.Profiles[i] = profile
+	} else {
+		cfg.Providers.Profiles = append(cfg.Providers.Profiles, profile)
+	}
+	syncActiveProvider(&cfg, nil)
+	if err := saveLocked(cfg); err != nil {
+		return ProviderProfile{}, err
+	}
+	return profile, nil
+}
+
+// DeleteProfile removes a provider profile. The last profile cannot be
+// deleted; stages bound to the deleted profile fall back to the active one.
+func DeleteProfile(id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	cfg := fileConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	i := findProfile(cfg.Providers.Profiles, id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+	}
+	if len(cfg.Providers.Profiles) == 1 {
+		return fmt.Errorf("cannot delete the last provider profile")
+	}
+
+	cfg.Providers.Profiles = append(cfg.Providers.Profiles[:i], cfg.Providers.Profiles[i+1:]...)
+	for stage, pid := range cfg.Providers.Stages {
+		if pid == id {
+			delete(cfg.Providers.Stages, stage)
+		}
+	}
+	syncActiveProvider(&cfg, nil)
+	return saveLocked(cfg)
+}
+
+// SetActiveProfile changes the profile used by stages without an override
+func SetActiveProfile(id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if findProfile(fileConfig.Providers.Profiles, id) < 0 {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+	}
+	cfg := fileConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	cfg.Providers.ActiveID = id
+	syncActiveProvider(&cfg, nil)
+	return saveLocked(cfg)
+}
+
+// SetStageProfile binds a pipeline stage to a profile. An empty id removes
+// the binding so the stage uses the active profile.
+func SetStageProfile(stage Stage, id string) error {
+	if !validStage(stage) {
+		return fmt.Errorf("unknown stage %q", stage)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if id != "" && findProfile(fileConfig.Providers.Profiles, id) < 0 {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+	}
+	cfg := fileConfig
+	cfg.Providers = cloneProviders(cfg.Providers)
+	if id == "" {
+		delete(cfg.Providers.Stages, stage)
+	} else {
+		cfg.Providers.Stages[stage] = id
+	}
+	return saveLocked(cfg)
+}
+
+// newProfileID turns a profile name into a unique ID
+func newProfileID(profiles []ProviderProfile, name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	base := strings.Trim(b.String(), "-")
+	if base == "" {
+		base = "profile"
+	}
+	id := base
+	for n := 2; findProfile(profiles, id) >= 0; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/secrets"
+)
+
+func TestProviderProfiles(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(Config{AI: AIConfig{Provider: "ollama", Model: "llama3"}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A legacy config gets a default profile built from AI
+	if profiles := ListProfiles(); len(profiles) != 1 || profiles[0].Model != "llama3" {
+		t.Fatalf("expected migrated default profile, got %+v", profiles)
+	}
+
+	hosted, err := SaveProfile(ProviderProfile{Name: "Hosted GPT", Provider: "openai", Model: "gpt-4o", Temperature: 0.2})
+	if err != nil {
+		t.Fatalf("SaveProfile failed: %v", err)
+	}
+	if hosted.ID != "hosted-gpt" {
+		t.Errorf("expected id hosted-gpt, got %s", hosted.ID)
+	}
+
+	// Stages fall back to the active profile unless bound
+	if err := SetStageProfile(StageSlides, hosted.ID); err != nil {
+		t.Fatalf("SetStageProfile failed: %v", err)
+	}
+	if p, _ := ProfileForStage(StageSlides); p.ID != hosted.ID {
+		t.Errorf("expected slides stage to use %s, got %s", hosted.ID, p.ID)
+	}
+	if p, _ := ProfileForStage(StageOutline); p.ID != "default" {
+		t.Errorf("expected outline stage to use default, got %s", p.ID)
+	}
+
+	// Editing AI directly updates the active profile
+	cfg := Get()
+	cfg.AI.Model = "llama3.1"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if p, _ := GetProfile("default"); p.Model != "llama3.1" {
+		t.Errorf("expected AI edit to reach active profile, got %+v", p)
+	}
+
+	// Switching the active profile is reflected in AI
+	if err := SetActiveProfile(hosted.ID); err != nil {
+		t.Fatalf("SetActiveProfile failed: %v", err)
+	}
+	if Get().AI.Model != "gpt-4o" {
+		t.Errorf("expected AI to mirror active profile, got %+v", Get().AI)
+	}
+
+	// Deleting a profile clears its stage bindings
+	if err := DeleteProfile(hosted.ID); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if p, _ := ProfileForStage(StageSlides); p.ID != "default" {
+		t.Errorf("expected slides stage to fall back to default, got %s", p.ID)
+	}
+	if err := DeleteProfile("default"); err == nil {
+		t.Errorf("expected deleting the last profile to fail")
+	}
+}
+
+func TestAPIKeysStayOutOfConfigFile(t *testing.T) {
+	useTempConfig(t)
+
+	// A config written by an older version with a plain-text key
+	legacy := `{"ai": {"provider": "openai", "apiKey": "sk-legacy", "model": "gpt-4o"}}`
+	if err := os.WriteFile(configPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AI.APIKey != "sk-legacy" {
+		t.Errorf("expected key to be available in memory, got %q", cfg.AI.APIKey)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-legacy") {
+		t.Fatalf("plain-text key left in config file: %s", data)
+	}
+
+	// Keys are resolved from the secret store on the next load
+	currentConfig, fileConfig = Config{}, Config{}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if p := ActiveProfile(); p.APIKey != "sk-legacy" || p.APIKeyRef == "" {
+		t.Errorf("expected key resolved from store, got %+v", p)
+	}
+}
+
+// lockedStore fails to read secrets, like a keyring that is locked
+type lockedStore struct{ secrets.Store }
+
+func (lockedStore) Get(string) (string, error) { return "", errors.New("keyring is locked") }
+
+func TestUnresolvedKeysSurviveSave(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(Config{AI: AIConfig{Provider: "openai", APIKey: "sk-kept", Model: "gpt-4o", BaseURL: "https://api.openai.com/v1"}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A failed unlock followed by an unrelated settings change
+	open := openSecrets
+	openSecrets = func() secrets.Store { return lockedStore{open()} }
+	currentConfig, fileConfig = Config{}, Config{}
+	if _, err := Load(); err == nil {
+		t.Fatal("expected Load to report the unreadable key")
+	}
+	if err := SelectStyle("tech"); err != nil {
+		t.Fatalf("SelectStyle failed: %v", err)
+	}
+	openSecrets = open
+
+	currentConfig, fileConfig = Config{}, Config{}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if p := ActiveProfile(); p.APIKey != "sk-kept" {
+		t.Fatalf("key lost after saving with a locked store: %+v", p)
+	}
+
+	// Clearing the key removes it from the store
+	cfg := Get()
+	ref := cfg.Providers.Profiles[0].APIKeyRef
+	cfg.Providers.Profiles[0].APIKey, cfg.Providers.Profiles[0].APIKeyRef = "", ""
+	cfg.AI.APIKey = ""
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := openSecrets().Get(ref); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("expected cleared key to be deleted, got %v", err)
+	}
+}
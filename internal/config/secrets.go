@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"slidev-studio-ai/internal/secrets"
+)
+
+// openSecrets returns the store API keys are kept in. The file store lives
+// next to the config file.
+var openSecrets = func() secrets.Store {
+	return secrets.Open(filepath.Dir(configPath))
+}
+
+func secretKey(profileID string) string {
+	return "provider/" + profileID
+}
+
+// hasPlaintextKeys reports whether cfg, as read from disk, still carries API
+// keys in clear text (configs written before the secret store existed)
+func hasPlaintextKeys(cfg Config) bool {
+	if cfg.AI.APIKey != "" {
+		return true
+	}
+	for _, p := range cfg.Providers.Profiles {
+		if p.APIKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// unresolvedRefs holds the secret references the last resolveSecrets could
+// not read, e.g. with a locked keyring. Their profiles have no key in memory,
+// which must not be taken for the user removing it. Guarded by mutex.
+var unresolvedRefs = map[string]bool{}
+
+// resolveSecrets fills in API keys for profiles that reference the store
+func resolveSecrets(cfg *Config) error {
+	store := openSecrets()
+	unresolvedRefs = map[string]bool{}
+	var errs []error
+	for i := range cfg.Providers.Profiles {
+		p := &cfg.Providers.Profiles[i]
+		if p.APIKeyRef == "" || p.APIKey != "" {
+			continue
+		}
+		key, err := store.Get(p.APIKeyRef)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("api key for profile %q: %w", p.ID, err))
+			unresolvedRefs[p.APIKeyRef] = true
+			continue
+		}
+		p.APIKey = key
+	}
+	return errors.Join(errs...)
+}
+
+// storeSecrets moves the API keys of cfg into the secret store, records the
+// references on cfg and returns a copy of cfg without keys for writing to
+// disk. Secrets of profiles that were removed or cleared since prev are
+// deleted. A profile without a key keeps a reference that could not be
+// resolved, unless the reference was cleared too.
+func storeSecrets(cfg *Config, prev Config) (Config, error) {
+	store := openSecrets()
+	kept := map[string]bool{}
+
+	for i := range cfg.Providers.Profiles {
+		p := &cfg.Providers.Profiles[i]
+		if p.APIKey == "" {
+			if unresolvedRefs[p.APIKeyRef] {
+				kept[p.APIKeyRef] = true
+			} else {
+				p.APIKeyRef = ""
+			}
+			continue
+		}
+		ref := secretKey(p.ID)
+		unchanged := false
+		if j := findProfile(prev.Providers.Profiles, p.ID); j >= 0 {
+			old := prev.Providers.Profiles[j]
+			unchanged = old.APIKeyRef == ref && old.APIKey == p.APIKey
+		}
+		if !unchanged {
+			if err := store.Set(ref, p.APIKey); err != nil {
+				return Config{}, fmt.Errorf("failed to store api key for profile %q: %w", p.ID, err)
+			}
+		}
+		p.APIKeyRef = ref
+		kept[ref] = true
+		delete(unresolvedRefs, ref)
+	}
+	for _, p := range prev.Providers.Profiles {
+		if p.APIKeyRef != "" && !kept[p.APIKeyRef] {
+			_ = store.Delete(p.APIKeyRef)
+		}
+	}
+
+	disk := *cfg
+	disk.AI.APIKey = ""
+	disk.Providers.Profiles = append([]ProviderProfile(nil), cfg.Providers.Profiles...)
+	for i := range disk.Providers.Profiles {
+		disk.Providers.Profiles[i].APIKey = ""
+	}
+	return disk, nil
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// StylePackVersion is the current version of the style pack file format
+const StylePackVersion = 1
+
+// StylePack is a shareable file containing one or more prompt styles
+type StylePack struct {
+	Version  int           `json:"version"`
+	Exported time.Time     `json:"exported"`
+	Styles   []PromptStyle `json:"styles"`
+	Checksum string        `json:"checksum"` // sha256 of the JSON-encoded styles
+}
+
+// ConflictStrategy decides what happens when an imported style ID already exists
+type ConflictStrategy string
+
+const (
+	ConflictRename    ConflictStrategy = "rename"
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	ConflictSkip      ConflictStrategy = "skip"
+)
+
+// ImportResult reports what happened to each style in an imported pack
+type ImportResult struct {
+	Imported    []string          `json:"imported"`    // IDs of styles added as new
+	Renamed     map[string]string `json:"renamed"`     // original ID -> stored ID
+	Overwritten []string          `json:"overwritten"` // IDs whose existing version was replaced
+	Skipped     []string          `json:"skipped"`     // IDs left untouched
+}
+
+func stylesChecksum(styles []PromptStyle) (string, error) {
+	data, err := json.Marshal(styles)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ExportStyles writes the given styles to a style pack file. When ids is
+// empty, all custom and edited builtin styles are exported.
+func ExportStyles(ids []string, path string) error {
+	var styles []PromptStyle
+	if len(ids) == 0 {
+		mutex.RLock()
+		styles = cloneStyles(currentConfig.Prompts.CustomStyles)
+		mutex.RUnlock()
+	} else {
+		for _, id := range ids {
+			style, err := GetStyle(id)
+			if err != nil {
+				return err
+			}
+			styles = append(styles, style)
+		}
+	}
+	if len(styles) == 0 {
+		return fmt.Errorf("no styles to export")
+	}
+
+	checksum, err := stylesChecksum(styles)
+	if err != nil {
+		return err
+	}
+	pack := StylePack{
+		Version:  StylePackVersion,
+		Exported: time.Now().UTC(),
+		Styles:   styles,
+		Checksum: checksum,
+	}
+	data, err := json.MarshalIndent(pack, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadStylePack loads a style pack file and verifies its version and checksum
+func ReadStylePack(path string) (*StylePack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pack StylePack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("invalid style pack: %w", err)
+	}
+	if pack.Version < 1 || pack.Version > StylePackVersion {
+		return nil, fmt.Errorf("unsupported style pack version %d", pack.Version)
+	}
+	checksum, err := stylesChecksum(pack.Styles)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != pack.Checksum {
+		return nil, fmt.Errorf("style pack checksum mismatch: file is corrupted or was modified")
+	}
+	for _, style := range pack.Styles {
+		if err := ValidateStyle(style); err != nil {
+			return nil, fmt.Errorf("style %q: %w", style.ID, err)
+		}
+	}
+	return &pack, nil
+}
+
+// ImportStyles adds the styles from a style pack file, resolving ID
+// conflicts with the given strategy (rename when empty)
+func ImportStyles(path string, strategy ConflictStrategy) (*ImportResult, error) {
+	switch strategy {
+	case "":
+		strategy = ConflictRename
+	case ConflictRename, ConflictOverwrite, ConflictSkip:
+	default:
+		return nil, fmt.Errorf("unknown conflict strategy %q", strategy)
+	}
+
+	pack, err := ReadStylePack(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	custom := cloneStyles(fileConfig.Prompts.CustomStyles)
+	result := &ImportResult{Renamed: map[string]string{}}
+	for _, style := range pack.Styles {
+		_, isBuiltin := builtinStyle(style.ID)
+		i := findStyle(custom, style.ID)
+		exists := isBuiltin || i >= 0
+
+		switch {
+		case !exists:
+			style.IsBuiltin = false
+			custom = append(custom, style)
+			result.Imported = append(result.Imported, style.ID)
+		case strategy == ConflictSkip:
+			result.Skipped = append(result.Skipped, style.ID)
+		case strategy == ConflictOverwrite:
+			style.IsBuiltin = isBuiltin
+			if i >= 0 {
+				custom[i] = style
+			} else {
+				custom = append(custom, style)
+			}
+			result.Overwritten = append(result.Overwritten, style.ID)
+		default:
+			original := style.ID
+			style.ID = renamedStyleID(mergeStyles(custom), original)
+			style.IsBuiltin = false
+			custom = append(custom, style)
+			result.Renamed[original] = style.ID
+		}
+	}
+
+	cfg := fileConfig
+	cfg.Prompts.CustomStyles = custom
+	if err := saveLocked(cfg); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func renamedStyleID(existing []PromptStyle, id string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d", id, n)
+		if findStyle(existing, candidate) < 0 {
+			return candidate
+		}
+	}
+}
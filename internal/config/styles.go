@@ -0,0 +1,279 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStyleID is used when no style has been selected yet
+const DefaultStyleID = "business"
+
+var (
+	ErrStyleNotFound = errors.New("prompt style not found")
+	ErrBuiltinStyle  = errors.New("builtin styles cannot be deleted")
+)
+
+// requiredSlidePlaceholders must appear in every SlidePrompt; the generation
+// pipeline relies on them to map slides back to outline entries.
+var requiredSlidePlaceholders = []string{"{slide_id}"}
+
+//go:embed builtin_styles.json
+var builtinStylesJSON []byte
+
+var (
+	builtinStyles     []PromptStyle
+	builtinStylesOnce sync.Once
+)
+
+// BuiltinStyles returns the preset styles shipped with the application
+func BuiltinStyles() []PromptStyle {
+	builtinStylesOnce.Do(func() {
+		if err := json.Unmarshal(builtinStylesJSON, &builtinStyles); err != nil {
+			panic(fmt.Sprintf("config: invalid builtin_styles.json: %v", err))
+		}
+		for i := range builtinStyles {
+			builtinStyles[i].IsBuiltin = true
+		}
+	})
+	styles := make([]PromptStyle, len(builtinStyles))
+	copy(styles, builtinStyles)
+	return styles
+}
+
+func builtinStyle(id string) (PromptStyle, bool) {
+	for _, s := range BuiltinStyles() {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return PromptStyle{}, false
+}
+
+// mergeStyles returns builtin styles (replaced by their edited versions when
+// present) followed by purely custom styles.
+func mergeStyles(custom []PromptStyle) []PromptStyle {
+	overrides := make(map[string]PromptStyle, len(custom))
+	for _, s := range custom {
+		overrides[s.ID] = s
+	}
+
+	builtins := BuiltinStyles()
+	builtinIDs := make(map[string]bool, len(builtins))
+	merged := make([]PromptStyle, 0, len(builtins)+len(custom))
+	for _, b := range builtins {
+		builtinIDs[b.ID] = true
+		if o, ok := overrides[b.ID]; ok {
+			o.IsBuiltin = true
+			merged = append(merged, o)
+			continue
+		}
+		merged = append(merged, b)
+	}
+	for _, s := range custom {
+		if !builtinIDs[s.ID] {
+			s.IsBuiltin = false
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+func findStyle(styles []PromptStyle, id string) int {
+	for i, s := range styles {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateStyle checks that a style has the fields and placeholders the
+// generation pipeline depends on
+func ValidateStyle(style PromptStyle) error {
+	if strings.TrimSpace(style.ID) == "" {
+		return fmt.Errorf("style id is required")
+	}
+	if strings.TrimSpace(style.Name) == "" {
+		return fmt.Errorf("style name is required")
+	}
+	if strings.TrimSpace(style.OutlinePrompt) == "" {
+		return fmt.Errorf("outline prompt is required")
+	}
+	if strings.TrimSpace(style.SlidePrompt) == "" {
+		return fmt.Errorf("slide prompt is required")
+	}
+	for _, p := range requiredSlidePlaceholders {
+		if !strings.Contains(style.SlidePrompt, p) {
+			return fmt.Errorf("slide prompt must contain the %s placeholder", p)
+		}
+	}
+	return nil
+}
+
+// ListStyles returns all available styles (builtin + custom)
+func ListStyles() []PromptStyle {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return mergeStyles(currentConfig.Prompts.CustomStyles)
+}
+
+// GetStyle returns the effective version of a style
+func GetStyle(id string) (PromptStyle, error) {
+	styles := ListStyles()
+	if i := findStyle(styles, id); i >= 0 {
+		return styles[i], nil
+	}
+	return PromptStyle{}, fmt.Errorf("%w: %s", ErrStyleNotFound, id)
+}
+
+// SelectedStyle returns the currently selected style, falling back to the
+// default style when the selection is empty or no longer exists
+func SelectedStyle() PromptStyle {
+	mutex.RLock()
+	id := currentConfig.Prompts.SelectedStyleID
+	mutex.RUnlock()
+
+	if style, err := GetStyle(id); err == nil {
+		return style
+	}
+	style, _ := GetStyle(DefaultStyleID)
+	return style
+}
+
+// CreateStyle adds a new custom style. An ID is generated when none is given.
+func CreateStyle(style PromptStyle) (PromptStyle, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	existing := mergeStyles(fileConfig.Prompts.CustomStyles)
+	if style.ID == "" {
+		style.ID = newStyleID(existing)
+	}
+	style.IsBuiltin = false
+	if err := ValidateStyle(style); err != nil {
+		return PromptStyle{}, err
+	}
+	if findStyle(existing, style.ID) >= 0 {
+		return PromptStyle{}, fmt.Errorf("style %q already exists", style.ID)
+	}
+
+	cfg := fileConfig
+	cfg.Prompts.CustomStyles = append(cloneStyles(cfg.Prompts.CustomStyles), style)
+	if err := saveLocked(cfg); err != nil {
+		return PromptStyle{}, err
+	}
+	return style, nil
+}
+
+// UpdateStyle replaces an existing style. Editing a builtin style stores an
+// override that can later be discarded with ResetStyle.
+func UpdateStyle(style PromptStyle) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	_, isBuiltin := builtinStyle(style.ID)
+	custom := cloneStyles(fileConfig.Prompts.CustomStyles)
+	i := findStyle(custom, style.ID)
+	if i < 0 && !isBuiltin {
+		return fmt.Errorf("%w: %s", ErrStyleNotFound, style.ID)
+	}
+
+	style.IsBuiltin = isBuiltin
+	if err := ValidateStyle(style); err != nil {
+		return err
+	}
+	if i >= 0 {
+		custom[i] = style
+	} else {
+		custom = append(custom, style)
+	}
+
+	cfg := fileConfig
+	cfg.Prompts.CustomStyles = custom
+	return saveLocked(cfg)
+}
+
+// DeleteStyle removes a custom style. Builtin styles are protected.
+func DeleteStyle(id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, ok := builtinStyle(id); ok {
+		return fmt.Errorf("%w: %s", ErrBuiltinStyle, id)
+	}
+	custom := cloneStyles(fileConfig.Prompts.CustomStyles)
+	i := findStyle(custom, id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrStyleNotFound, id)
+	}
+
+	cfg := fileConfig
+	cfg.Prompts.CustomStyles = append(custom[:i], custom[i+1:]...)
+	if cfg.Prompts.SelectedStyleID == id {
+		cfg.Prompts.SelectedStyleID = DefaultStyleID
+	}
+	return saveLocked(cfg)
+}
+
+// ResetStyle discards user edits to a builtin style and returns the original
+func ResetStyle(id string) (PromptStyle, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	builtin, ok := builtinStyle(id)
+	if !ok {
+		return PromptStyle{}, fmt.Errorf("%w: %s is not a builtin style", ErrStyleNotFound, id)
+	}
+	custom := cloneStyles(fileConfig.Prompts.CustomStyles)
+	if i := findStyle(custom, id); i >= 0 {
+		cfg := fileConfig
+		cfg.Prompts.CustomStyles = append(custom[:i], custom[i+1:]...)
+		if err := saveLocked(cfg); err != nil {
+			return PromptStyle{}, err
+		}
+	}
+	return builtin, nil
+}
+
+// DuplicateStyle copies any style (builtin or custom) into a new custom style
+func DuplicateStyle(id string) (PromptStyle, error) {
+	src, err := GetStyle(id)
+	if err != nil {
+		return PromptStyle{}, err
+	}
+	src.ID = ""
+	src.Name = src.Name + " (copy)"
+	return CreateStyle(src)
+}
+
+// SelectStyle makes a style the active one for generation
+func SelectStyle(id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if findStyle(mergeStyles(fileConfig.Prompts.CustomStyles), id) < 0 {
+		return fmt.Errorf("%w: %s", ErrStyleNotFound, id)
+	}
+	cfg := fileConfig
+	cfg.Prompts.SelectedStyleID = id
+	return saveLocked(cfg)
+}
+
+// newStyleID generates a custom style ID that does not clash with existing ones
+func newStyleID(existing []PromptStyle) string {
+	base := fmt.Sprintf("custom_%d", time.Now().UnixMilli())
+	id := base
+	for n := 2; findStyle(existing, id) >= 0; n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	return id
+}
+
+func cloneStyles(styles []PromptStyle) []PromptStyle {
+	return append([]PromptStyle(nil), styles...)
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStyles(t *testing.T) {
+	useTempConfig(t)
+
+	if len(ListStyles()) != len(BuiltinStyles()) {
+		t.Fatalf("expected only builtin styles, got %d", len(ListStyles()))
+	}
+
+	// Create a custom style; a missing {slide_id} placeholder is rejected
+	style := PromptStyle{Name: "Mine", OutlinePrompt: "outline", SlidePrompt: "slides"}
+	if _, err := CreateStyle(style); err == nil {
+		t.Fatalf("expected placeholder validation error")
+	}
+	style.SlidePrompt = "<!-- slide_id: {slide_id} -->"
+	created, err := CreateStyle(style)
+	if err != nil {
+		t.Fatalf("CreateStyle failed: %v", err)
+	}
+	if created.ID == "" || created.IsBuiltin {
+		t.Errorf("unexpected created style: %+v", created)
+	}
+
+	// Duplicate and select
+	dup, err := DuplicateStyle(created.ID)
+	if err != nil {
+		t.Fatalf("DuplicateStyle failed: %v", err)
+	}
+	if dup.ID == created.ID || !strings.HasSuffix(dup.Name, "(copy)") {
+		t.Errorf("unexpected duplicate: %+v", dup)
+	}
+	if err := SelectStyle(dup.ID); err != nil {
+		t.Fatalf("SelectStyle failed: %v", err)
+	}
+	if SelectedStyle().ID != dup.ID {
+		t.Errorf("expected %s to be selected", dup.ID)
+	}
+
+	// Deleting the selected style falls back to the default
+	if err := DeleteStyle(dup.ID); err != nil {
+		t.Fatalf("DeleteStyle failed: %v", err)
+	}
+	if SelectedStyle().ID != DefaultStyleID {
+		t.Errorf("expected fallback to %s, got %s", DefaultStyleID, SelectedStyle().ID)
+	}
+
+	// Builtin styles can be edited and reset, but not deleted
+	edited, _ := GetStyle(DefaultStyleID)
+	edited.Description = "edited"
+	if err := UpdateStyle(edited); err != nil {
+		t.Fatalf("UpdateStyle failed: %v", err)
+	}
+	if got, _ := GetStyle(DefaultStyleID); got.Description != "edited" || !got.IsBuiltin {
+		t.Errorf("expected edited builtin, got %+v", got)
+	}
+	if err := DeleteStyle(DefaultStyleID); !errors.Is(err, ErrBuiltinStyle) {
+		t.Errorf("expected ErrBuiltinStyle, got %v", err)
+	}
+	original, err := ResetStyle(DefaultStyleID)
+	if err != nil {
+		t.Fatalf("ResetStyle failed: %v", err)
+	}
+	if got, _ := GetStyle(DefaultStyleID); got.Description != original.Description {
+		t.Errorf("expected reset description %q, got %q", original.Description, got.Description)
+	}
+
+	// Changes are persisted
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(ListStyles()) != len(BuiltinStyles())+1 {
+		t.Errorf("expected one custom style after reload, got %d", len(ListStyles())-len(BuiltinStyles()))
+	}
+}
+
+func TestStoredStylesOnlyCheckedWhenEdited(t *testing.T) {
+	useTempConfig(t)
+
+	// A style saved before {slide_id} was required
+	stored := `{"version": 1, "ai": {"provider": "ollama", "model": "llama3"}, "prompts": {"customStyles": [{"id": "old", "name": "Old", "outlinePrompt": "outline", "slidePrompt": "slides"}]}}`
+	if err := os.WriteFile(configPath, []byte(stored), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Unrelated settings can still be saved
+	cfg := Get()
+	cfg.Workspace = t.TempDir()
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := SelectStyle("old"); err != nil {
+		t.Fatalf("SelectStyle failed: %v", err)
+	}
+
+	// Editing the style checks it
+	cfg = Get()
+	cfg.Prompts.CustomStyles[0].Name = "Renamed"
+	var verr *ValidationError
+	if err := Save(cfg); !errors.As(err, &verr) || verr.Errors[0].Field != "prompts.customStyles[0]" {
+		t.Errorf("expected an error for the edited style, got %v", err)
+	}
+	old, _ := GetStyle("old")
+	if err := UpdateStyle(old); err == nil {
+		t.Errorf("expected UpdateStyle to check the style")
+	}
+}
+
+func TestStylePack(t *testing.T) {
+	dir := useTempConfig(t)
+
+	style, err := CreateStyle(PromptStyle{ID: "team", Name: "Team", OutlinePrompt: "outline", SlidePrompt: "{slide_id}"})
+	if err != nil {
+		t.Fatalf("CreateStyle failed: %v", err)
+	}
+	packPath := filepath.Join(dir, "team.slidevstyles")
+	if err := ExportStyles([]string{style.ID, DefaultStyleID}, packPath); err != nil {
+		t.Fatalf("ExportStyles failed: %v", err)
+	}
+
+	// Both IDs already exist, so skip leaves everything untouched
+	result, err := ImportStyles(packPath, ConflictSkip)
+	if err != nil {
+		t.Fatalf("ImportStyles failed: %v", err)
+	}
+	if len(result.Skipped) != 2 {
+		t.Errorf("expected 2 skipped styles, got %+v", result)
+	}
+
+	// Rename stores copies under fresh IDs as custom styles
+	result, err = ImportStyles(packPath, ConflictRename)
+	if err != nil {
+		t.Fatalf("ImportStyles failed: %v", err)
+	}
+	if result.Renamed["team"] != "team_2" || result.Renamed[DefaultStyleID] != DefaultStyleID+"_2" {
+		t.Errorf("unexpected renames: %+v", result.Renamed)
+	}
+	if got, err := GetStyle(DefaultStyleID + "_2"); err != nil || got.IsBuiltin {
+		t.Errorf("expected renamed builtin to be custom, got %+v (%v)", got, err)
+	}
+
+	// A tampered pack is rejected
+	pack, err := ReadStylePack(packPath)
+	if err != nil {
+		t.Fatalf("ReadStylePack failed: %v", err)
+	}
+	pack.Styles[0].Name = "tampered"
+	data, _ := json.Marshal(pack)
+	if err := os.WriteFile(packPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportStyles(packPath, ConflictOverwrite); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+}
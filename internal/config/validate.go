@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProviderTypes lists the AI providers the application can talk to
+var ProviderTypes = []string{"openai", "openai-compatible", "google", "anthropic", "ollama"}
+
+// FieldError describes an invalid config value
+type FieldError struct {
+	Field   string `json:"field"` // JSON path, e.g. "providers.profiles[1].baseUrl"
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by Save when one or more values are invalid
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks cfg and returns a *ValidationError listing every invalid
+// field, or nil. Custom styles are checked when they are saved, see
+// validateEditedStyles.
+func Validate(cfg Config) error {
+	verr := &ValidationError{}
+
+	if cfg.Workspace != "" {
+		if info, err := os.Stat(cfg.Workspace); err != nil || !info.IsDir() {
+			verr.add("workspace", "directory %q does not exist", cfg.Workspace)
+		}
+	}
+
+	ids := map[string]bool{}
+	for i, p := range cfg.Providers.Profiles {
+		field := fmt.Sprintf("providers.profiles[%d]", i)
+		switch {
+		case p.ID == "":
+			verr.add(field+".id", "is required")
+		case ids[p.ID]:
+			verr.add(field+".id", "duplicate profile id %q", p.ID)
+		}
+		ids[p.ID] = true
+		if strings.TrimSpace(p.Name) == "" {
+			verr.add(field+".name", "is required")
+		}
+		if !validProvider(p.Provider) {
+			verr.add(field+".provider", "must be one of %s", strings.Join(ProviderTypes, ", "))
+		}
+		if p.BaseURL != "" {
+			if err := validateURL(p.BaseURL); err != nil {
+				verr.add(field+".baseUrl", "%v", err)
+			}
+		}
+		if p.Temperature < 0 || p.Temperature > 2 {
+			verr.add(field+".temperature", "must be between 0 and 2")
+		}
+		if p.MaxTokens < 0 {
+			verr.add(field+".maxTokens", "must not be negative")
+		}
+		if p.Timeout < 0 {
+			verr.add(field+".timeout", "must not be negative")
+		}
+	}
+	if cfg.Providers.ActiveID != "" && !ids[cfg.Providers.ActiveID] {
+		verr.add("providers.activeId", "unknown profile %q", cfg.Providers.ActiveID)
+	}
+	for stage, id := range cfg.Providers.Stages {
+		if !validStage(stage) {
+			verr.add("providers.stages."+string(stage), "unknown stage")
+		} else if !ids[id] {
+			verr.add("providers.stages."+string(stage), "unknown profile %q", id)
+		}
+	}
+
+	switch cfg.Updates.Channel {
+	case "", "stable", "beta":
+	default:
+		verr.add("updates.channel", "must be stable or beta")
+	}
+	if r := cfg.Updates.Repository; r != "" {
+		owner, repo, ok := strings.Cut(r, "/")
+		if !ok || owner == "" || repo == "" || strings.ContainsAny(repo, "/ ") || strings.Contains(owner, " ") {
+			verr.add("updates.repository", "must be in owner/repo form")
+		}
+	}
+
+	if cfg.API.Port < 0 || cfg.API.Port > 65535 {
+		verr.add("api.port", "must be between 1 and 65535")
+	}
+	if cfg.Trash.RetentionDays < 0 {
+		verr.add("trash.retentionDays", "must not be negative")
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func validProvider(provider string) bool {
+	for _, p := range ProviderTypes {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must be an http or https URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("must include a host")
+	}
+	return nil
+}
+
+// validateEditedStyles checks the custom styles that differ from the stored
+// ones. Styles are only checked when created or updated, so that a style
+// stored before a rule was added does not block saving unrelated settings.
+func validateEditedStyles(styles, stored []PromptStyle) error {
+	verr := &ValidationError{}
+	for i, s := range styles {
+		if j := findStyle(stored, s.ID); j >= 0 && stored[j] == s {
+			continue
+		}
+		if err := ValidateStyle(s); err != nil {
+			verr.add(fmt.Sprintf("prompts.customStyles[%d]", i), "%v", err)
+		}
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+// Package httpapi serves a localhost HTTP/JSON API that lets other tools
+// (editors, scripts, launchers) drive the same decks as the app window.
+package httpapi
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Server is the local HTTP API. It shares Tools and the Slidev server with
+// the GUI, so edits from either side go through the same locks.
+type Server struct {
+	Tools  *slidev.Tools
+	Slidev *slidev.Server
+	Token  string       // Required as "Authorization: Bearer <token>"; change with SetToken
+	MCP    http.Handler // Served at /mcp behind the same token when set
+
+	mu       sync.Mutex
+	srv      *http.Server
+	listener net.Listener
+}
+
+// NewServer returns an API server for the given tools and Slidev server
+func NewServer(tools *slidev.Tools, slidevServer *slidev.Server, token string) *Server {
+	return &Server{Tools: tools, Slidev: slidevServer, Token: token}
+}
+
+// Start listens on the loopback interface and serves the API in the
+// background. It returns the base URL.
+func (s *Server) Start(port int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv != nil {
+		return "http://" + s.listener.Addr().String(), nil
+	}
+	if s.Token == "" {
+		return "", fmt.Errorf("API token is required")
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return "", fmt.Errorf("failed to start API server: %w", err)
+	}
+	s.listener = l
+	s.srv = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go s.srv.Serve(l)
+	return "http://" + l.Addr().String(), nil
+}
+
+// Stop shuts the API server down
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv == nil {
+		return nil
+	}
+	err := s.srv.Close()
+	s.srv, s.listener = nil, nil
+	return err
+}
+
+// SetToken replaces the token clients must send
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Token = token
+}
+
+func (s *Server) token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Token
+}
+
+// URL returns the base URL while the server is running
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return ""
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// Handler returns the API's HTTP handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+	})
+
+	api := http.NewServeMux()
+	api.HandleFunc("GET /api/v1/projects", s.listProjects)
+	api.HandleFunc("POST /api/v1/projects", s.createProject)
+	api.HandleFunc("DELETE /api/v1/projects/{name}", s.deleteProject)
+	api.HandleFunc("GET /api/v1/projects/{name}/slides", s.readSlides)
+	api.HandleFunc("PUT /api/v1/projects/{name}/slides", s.saveSlides)
+	api.HandleFunc("PUT /api/v1/projects/{name}/pages/{index}", s.updatePage)
+	api.HandleFunc("POST /api/v1/projects/{name}/pages", s.insertPage)
+	api.HandleFunc("PUT /api/v1/projects/{name}/theme", s.applyTheme)
+	api.HandleFunc("GET /api/v1/projects/{name}/changes", s.listChangeSets)
+	api.HandleFunc("POST /api/v1/projects/{name}/changes/{id}", s.applyChangeSet)
+	api.HandleFunc("DELETE /api/v1/projects/{name}/changes/{id}", s.discardChangeSet)
+	api.HandleFunc("GET /api/v1/server", s.serverStatus)
+	api.HandleFunc("POST /api/v1/server", s.startServer)
+	api.HandleFunc("DELETE /api/v1/server", s.stopServer)
+	mux.Handle("/api/", s.authenticate(api))
+	if s.MCP != nil {
+		mux.Handle("/mcp", s.authenticate(s.MCP))
+	}
+
+	return localOnly(mux)
+}
+
+// localOnly rejects requests whose Host is not a loopback name, so web pages
+// cannot reach the API through DNS rebinding
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token())) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
+
+// writeToolError maps errors of the deck tools to HTTP statuses
+func writeToolError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, slidev.ErrChangeSetNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, slidev.ErrProjectExists), errors.Is(err, slidev.ErrChangeSetStale):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, errBadRequest), errors.Is(err, slidev.ErrPageOutOfRange), errors.Is(err, slidev.ErrInvalidPath):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+var errBadRequest = errors.New("bad request")
+
+// decode reads a JSON request body into v
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 16<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid JSON body: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+// deckName validates a deck name from a request: a slash separated path to a
+// deck file in the workspace, checked as the tools check it. In URLs the
+// slashes are escaped as %2F. The .md extension is optional.
+func (s *Server) deckName(raw string) (string, error) {
+	name := raw
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+	if _, err := slidev.ResolvePath(s.Tools.Dir(), name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := s.Tools.ListProjects()
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	if projects == nil {
+		projects = []slidev.Project{}
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+		slidev.CreateOptions
+	}
+	if err := decode(r, &body); err != nil {
+		writeToolError(w, err)
+		return
+	}
+	name, err := s.deckName(body.Name)
+	if err == nil {
+		name, err = s.Tools.CreateProjectWithOptions(name, body.CreateOptions)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"name": name})
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = s.Tools.DeleteProject(name)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type slidesBody struct {
+	Content string `json:"content"`
+}
+
+func (s *Server) readSlides(w http.ResponseWriter, r *http.Request) {
+	name, err := s.deckName(r.PathValue("name"))
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	content, err := s.Tools.ReadSlides(name)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slidesBody{Content: content})
+}
+
+func (s *Server) saveSlides(w http.ResponseWriter, r *http.Request) {
+	var body slidesBody
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = decode(r, &body)
+	}
+	if err == nil {
+		err = s.Tools.SaveSlides(name, body.Content)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updatePage proposes replacing the content of a page. Like the other page
+// and theme edits it is stored as a change set for the user to review,
+// which clients can accept or discard through the changes endpoints.
+func (s *Server) updatePage(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Markdown string `json:"markdown"`
+		Summary  string `json:"summary"`
+	}
+	name, err := s.deckName(r.PathValue("name"))
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid page index %q", r.PathValue("index")))
+		return
+	}
+	if err := decode(r, &body); err != nil {
+		writeToolError(w, err)
+		return
+	}
+	cs, err := s.Tools.ProposePageUpdate(name, index, body.Markdown, body.Summary)
+	writeProposed(w, cs, err)
+}
+
+func (s *Server) insertPage(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		After   int    `json:"after"`
+		Layout  string `json:"layout"`
+		Summary string `json:"summary"`
+	}
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = decode(r, &body)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	cs, err := s.Tools.ProposePageInsert(name, body.After, body.Layout, body.Summary)
+	writeProposed(w, cs, err)
+}
+
+func (s *Server) applyTheme(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Theme   string `json:"theme"`
+		Summary string `json:"summary"`
+	}
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = decode(r, &body)
+	}
+	if err == nil && body.Theme == "" {
+		err = fmt.Errorf("%w: theme is required", errBadRequest)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	cs, err := s.Tools.ProposeGlobalTheme(name, body.Theme, body.Summary)
+	writeProposed(w, cs, err)
+}
+
+// writeProposed writes the result of a proposal: the change set, or no
+// content when the edit changes nothing
+func writeProposed(w http.ResponseWriter, cs *slidev.ChangeSet, err error) {
+	switch {
+	case err != nil:
+		writeToolError(w, err)
+	case cs == nil:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusCreated, cs)
+	}
+}
+
+func (s *Server) listChangeSets(w http.ResponseWriter, r *http.Request) {
+	name, err := s.deckName(r.PathValue("name"))
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	sets, err := s.Tools.ListChangeSets(name)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sets)
+}
+
+// applyChangeSet writes the accepted hunks of a change set and rejects the
+// others
+func (s *Server) applyChangeSet(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Accept []int `json:"accept"`
+	}
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = decode(r, &body)
+	}
+	if err == nil {
+		err = s.Tools.ApplyChangeSet(name, r.PathValue("id"), body.Accept)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) discardChangeSet(w http.ResponseWriter, r *http.Request) {
+	name, err := s.deckName(r.PathValue("name"))
+	if err == nil {
+		err = s.Tools.DiscardChangeSet(name, r.PathValue("id"))
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type serverBody struct {
+	Running bool   `json:"running"`
+	URL     string `json:"url,omitempty"`
+}
+
+func (s *Server) serverStatus(w http.ResponseWriter, r *http.Request) {
+	url := s.Slidev.GetURL()
+	writeJSON(w, http.StatusOK, serverBody{Running: url != "", URL: url})
+}
+
+func (s *Server) startServer(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Project string `json:"project"`
+	}
+	if err := decode(r, &body); err != nil {
+		writeToolError(w, err)
+		return
+	}
+	name, err := s.deckName(body.Project)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	path, err := s.Tools.Path(name)
+	if err == nil {
+		_, err = os.Stat(path)
+	}
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	url, err := s.Slidev.Start(s.Tools.Dir(), name)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, serverBody{Running: true, URL: url})
+}
+
+func (s *Server) stopServer(w http.ResponseWriter, r *http.Request) {
+	if err := s.Slidev.Stop(); err != nil {
+		writeToolError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,208 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+func TestAPI(t *testing.T) {
+	workspace := t.TempDir()
+	tools := slidev.NewTools(workspace)
+	srv := httptest.NewServer(NewServer(tools, slidev.NewServer(), "s3cret").Handler())
+	defer srv.Close()
+
+	do := func(method, path, body string, token string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	call := func(method, path, body string) (int, string) {
+		return do(method, path, body, "s3cret")
+	}
+
+	if code, _ := do("GET", "/api/v1/projects", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", code)
+	}
+	if code, _ := do("GET", "/api/v1/projects", "", "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong token, got %d", code)
+	}
+	if code, body := do("GET", "/openapi.json", "", ""); code != http.StatusOK || !strings.Contains(body, `"openapi"`) {
+		t.Errorf("expected the OpenAPI description without auth, got %d", code)
+	}
+
+	if code, body := call("POST", "/api/v1/projects", `{"name": "talk"}`); code != http.StatusCreated {
+		t.Fatalf("create: %d %s", code, body)
+	}
+	if code, _ := call("POST", "/api/v1/projects", `{"name": "talk.md"}`); code != http.StatusConflict {
+		t.Errorf("expected 409 for an existing deck, got %d", code)
+	}
+	if code, _ := call("POST", "/api/v1/projects", `{"name": "../escape"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a path outside the workspace, got %d", code)
+	}
+
+	code, body := call("GET", "/api/v1/projects", "")
+	var projects []slidev.Project
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &projects) != nil || len(projects) != 1 {
+		t.Fatalf("list: %d %s", code, body)
+	}
+
+	// Page and theme edits are proposed as one change set for review
+	if code, body := call("PUT", "/api/v1/projects/talk/theme", `{"theme": "default"}`); code != http.StatusCreated {
+		t.Fatalf("theme: %d %s", code, body)
+	}
+	if code, body := call("POST", "/api/v1/projects/talk/pages", `{"after": 0, "layout": "center"}`); code != http.StatusCreated {
+		t.Fatalf("insert page: %d %s", code, body)
+	}
+	if code, body := call("PUT", "/api/v1/projects/talk/pages/1", `{"markdown": "# From the API", "summary": "Fill in"}`); code != http.StatusCreated || !strings.Contains(body, `"hunks"`) {
+		t.Fatalf("update page: %d %s", code, body)
+	}
+	if code, _ := call("PUT", "/api/v1/projects/talk/pages/99", `{"markdown": "x"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a page out of range, got %d", code)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); strings.Contains(content, "# From the API") {
+		t.Errorf("deck written before review:\n%s", content)
+	}
+
+	code, body = call("GET", "/api/v1/projects/talk/changes", "")
+	var sets []slidev.ChangeSet
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &sets) != nil || len(sets) != 1 || len(sets[0].Hunks) != 2 {
+		t.Fatalf("changes: %d %s", code, body)
+	}
+	if code, _ := call("POST", "/api/v1/projects/talk/changes/0-missing", `{"accept": [0]}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing change set, got %d", code)
+	}
+	if code, body := call("POST", "/api/v1/projects/talk/changes/"+sets[0].ID, `{"accept": [0, 1]}`); code != http.StatusNoContent {
+		t.Fatalf("apply: %d %s", code, body)
+	}
+
+	code, body = call("GET", "/api/v1/projects/talk/slides", "")
+	var slides struct{ Content string }
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &slides) != nil {
+		t.Fatalf("read: %d %s", code, body)
+	}
+	if !strings.Contains(slides.Content, "theme: default") || !strings.Contains(slides.Content, "layout: center\n---\n\n# From the API") {
+		t.Errorf("edits missing from deck:\n%s", slides.Content)
+	}
+
+	if code, _ := call("PUT", "/api/v1/projects/talk/pages/0", `{"markdown": "# Rejected"}`); code != http.StatusCreated {
+		t.Fatalf("propose page: %d", code)
+	}
+	sets, _ = tools.ListChangeSets("talk.md")
+	if code, _ := call("DELETE", "/api/v1/projects/talk/changes/"+sets[0].ID, ""); code != http.StatusNoContent {
+		t.Errorf("discard: %d", code)
+	}
+	if sets, _ := tools.ListChangeSets("talk.md"); len(sets) != 0 {
+		t.Errorf("expected no pending change sets, got %+v", sets)
+	}
+
+	// Writes go through the shared Tools, so the GUI sees them
+	if code, _ := call("PUT", "/api/v1/projects/talk/slides", `{"content": "# Replaced\n"}`); code != http.StatusNoContent {
+		t.Fatalf("save: %d", code)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); content != "# Replaced\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	// Decks in folders are named with escaped slashes
+	if err := os.Mkdir(filepath.Join(workspace, "talks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.SaveSlides("talks/q3.md", "# Q3\n"); err != nil {
+		t.Fatal(err)
+	}
+	if code, body := call("GET", "/api/v1/projects/talks%2Fq3/slides", ""); code != http.StatusOK || !strings.Contains(body, "# Q3") {
+		t.Errorf("read deck in a folder: %d %s", code, body)
+	}
+	if code, body := call("PUT", "/api/v1/projects/talks%2Fq3/pages/0", `{"markdown": "# Q3 results"}`); code != http.StatusCreated {
+		t.Errorf("update deck in a folder: %d %s", code, body)
+	}
+	for _, name := range []string{"..%2Fescape", "talks%2F.hidden", "talks%5Cq3"} {
+		if code, _ := call("GET", "/api/v1/projects/"+name+"/slides", ""); code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", name, code)
+		}
+	}
+
+	if code, _ := call("GET", "/api/v1/projects/missing/slides", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing deck, got %d", code)
+	}
+	if code, _ := call("POST", "/api/v1/server", `{"project": "missing"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 when serving a missing deck, got %d", code)
+	}
+	if code, body := call("GET", "/api/v1/server", ""); code != http.StatusOK || !strings.Contains(body, `"running":false`) {
+		t.Errorf("server status: %d %s", code, body)
+	}
+	if code, _ := call("DELETE", "/api/v1/projects/talk", ""); code != http.StatusNoContent {
+		t.Errorf("delete: %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "talk.md")); !os.IsNotExist(err) {
+		t.Errorf("deck not deleted")
+	}
+}
+
+func TestAPIRejectsForeignHosts(t *testing.T) {
+	h := NewServer(slidev.NewTools(t.TempDir()), slidev.NewServer(), "s3cret").Handler()
+	req := httptest.NewRequest("GET", "/api/v1/projects", nil)
+	req.Host = "attacker.example:17321"
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a non-loopback Host, got %d", rec.Code)
+	}
+}
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	token, err := LoadToken(dir)
+	if err != nil || len(token) != 64 {
+		t.Fatalf("LoadToken = %q, %v", token, err)
+	}
+	if again, _ := LoadToken(dir); again != token {
+		t.Errorf("token changed between loads")
+	}
+	if info, _ := os.Stat(filepath.Join(dir, TokenFile)); info.Mode().Perm()&0077 != 0 {
+		t.Errorf("token file is readable by others: %v", info.Mode())
+	}
+	if next, _ := RegenerateToken(dir); next == token {
+		t.Errorf("expected a new token")
+	}
+}
+
+func TestMCPRequiresToken(t *testing.T) {
+	s := NewServer(slidev.NewTools(t.TempDir()), slidev.NewServer(), "s3cret")
+	s.MCP = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h := s.Handler()
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "s3cret": http.StatusTeapot} {
+		req := httptest.NewRequest("POST", "http://127.0.0.1:17321/mcp", strings.NewReader("{}"))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("token %q: expected %d, got %d", token, want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenFile is the name of the file holding the API token. Clients on the
+// same machine read it to authenticate; it is only readable by the user.
+const TokenFile = "api-token"
+
+// LoadToken returns the API token stored in dir, creating one on first use
+func LoadToken(dir string) (string, error) {
+	path := filepath.Join(dir, TokenFile)
+	if data, err := os.ReadFile(path); err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	return RegenerateToken(dir)
+}
+
+// RegenerateToken replaces the API token in dir, locking out existing clients
+func RegenerateToken(dir string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, TokenFile), []byte(token+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
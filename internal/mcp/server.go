@@ -0,0 +1,172 @@
+// Package mcp implements a Model Context Protocol server that exposes the deck
+// tools to external AI agents, over stdio or streamable HTTP.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+// ProtocolVersions lists the MCP revisions the server speaks, newest first
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Server answers MCP requests using the shared deck tools
+type Server struct {
+	Tools   *slidev.Tools
+	Name    string
+	Version string
+}
+
+// NewServer returns an MCP server for the given tools
+func NewServer(tools *slidev.Tools, version string) *Server {
+	return &Server{Tools: tools, Name: "slidev-studio-ai", Version: version}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+func errorf(code int, format string, args ...any) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// HandleMessage processes one JSON-RPC message, which may be a batch, and
+// returns the encoded reply. It returns nil when nothing needs to be sent
+// back, as for notifications.
+func (s *Server) HandleMessage(ctx context.Context, msg []byte) []byte {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err == nil {
+		var replies []response
+		for _, m := range batch {
+			if r := s.handle(ctx, m); r != nil {
+				replies = append(replies, *r)
+			}
+		}
+		if len(replies) == 0 {
+			return nil
+		}
+		data, _ := json.Marshal(replies)
+		return data
+	}
+	r := s.handle(ctx, msg)
+	if r == nil {
+		return nil
+	}
+	data, _ := json.Marshal(r)
+	return data
+}
+
+func (s *Server) handle(ctx context.Context, msg json.RawMessage) *response {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: errorf(codeParseError, "parse error: %v", err)}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.ID == nil {
+			req.ID = json.RawMessage("null")
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: errorf(codeInvalidRequest, "invalid request")}
+	}
+
+	result, err := s.call(ctx, req.Method, req.Params)
+	if req.ID == nil {
+		// Notifications never get a reply, not even an error
+		return nil
+	}
+	if err != nil {
+		rpcErr, ok := err.(*rpcError)
+		if !ok {
+			rpcErr = errorf(codeInternalError, "%v", err)
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) call(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return s.initialize(params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]any{"tools": toolDefs}, nil
+	case "tools/call":
+		return s.callTool(ctx, params)
+	case "resources/list":
+		return s.listResources()
+	case "resources/templates/list":
+		return map[string]any{"resourceTemplates": []resourceTemplate{deckTemplate}}, nil
+	case "resources/read":
+		return s.readResource(params)
+	default:
+		if strings.HasPrefix(method, "notifications/") {
+			return nil, nil
+		}
+		return nil, errorf(codeMethodNotFound, "method %q not found", method)
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	version := ProtocolVersions[0]
+	if slices.Contains(ProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools":     map[string]any{},
+			"resources": map[string]any{},
+		},
+		"serverInfo": map[string]string{"name": s.Name, "version": s.Version},
+		"instructions": "Decks are Slidev markdown files in the app's workspace. Read a deck before editing it; " +
+			"page indexes start at 0 and do not count the headmatter.",
+	}, nil
+}
+
+func unmarshalParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+func newTestServer(t *testing.T) (*Server, *slidev.Tools) {
+	tools := slidev.NewTools(t.TempDir())
+	if err := tools.CreateProject("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(tools, "v1.2.3"), tools
+}
+
+// rpc sends one request and decodes the reply into a generic response
+func rpc(t *testing.T, s *Server, method string, params any) (result map[string]any, rpcErr *rpcError) {
+	t.Helper()
+	msg, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	reply := s.HandleMessage(context.Background(), msg)
+	var resp struct {
+		Result map[string]any
+		Error  *rpcError
+	}
+	if err := json.Unmarshal(reply, &resp); err != nil {
+		t.Fatalf("%s: invalid reply %s", method, reply)
+	}
+	return resp.Result, resp.Error
+}
+
+func TestServer(t *testing.T) {
+	s, tools := newTestServer(t)
+
+	result, _ := rpc(t, s, "initialize", map[string]any{"protocolVersion": "2025-03-26", "capabilities": map[string]any{}})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("expected the client's protocol version, got %v", result["protocolVersion"])
+	}
+	if result, _ := rpc(t, s, "initialize", map[string]any{"protocolVersion": "1999-01-01"}); result["protocolVersion"] != ProtocolVersions[0] {
+		t.Errorf("expected the latest protocol version, got %v", result["protocolVersion"])
+	}
+	if reply := s.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`)); reply != nil {
+		t.Errorf("notifications must not be answered, got %s", reply)
+	}
+
+	result, _ = rpc(t, s, "tools/list", nil)
+	if got := len(result["tools"].([]any)); got != len(toolDefs) {
+		t.Errorf("expected %d tools, got %d", len(toolDefs), got)
+	}
+
+	result, _ = rpc(t, s, "tools/call", map[string]any{"name": "update_page", "arguments": map[string]any{
+		"project": "talk", "page": 0, "markdown": "# Written by an agent",
+	}})
+	if result["isError"] == true {
+		t.Fatalf("update_page failed: %v", result)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); strings.Contains(content, "# Written by an agent") {
+		t.Errorf("page written before review:\n%s", content)
+	}
+	sets, err := tools.ListChangeSets("talk.md")
+	if err != nil || len(sets) != 1 {
+		t.Fatalf("expected one proposed change set, got %v (%v)", sets, err)
+	}
+	if err := tools.ApplyChangeSet("talk.md", sets[0].ID, []int{0}); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); !strings.Contains(content, "# Written by an agent") {
+		t.Errorf("page not updated:\n%s", content)
+	}
+
+	// Inserting pages and setting the theme are proposed as well
+	before, _ := tools.ReadSlides("talk.md")
+	for name, args := range map[string]map[string]any{
+		"insert_page": {"project": "talk", "after": 0, "layout": "center"},
+		"apply_theme": {"project": "talk", "theme": "apple-basic"},
+	} {
+		if result, _ := rpc(t, s, "tools/call", map[string]any{"name": name, "arguments": args}); result["isError"] == true {
+			t.Fatalf("%s failed: %v", name, result)
+		}
+	}
+	if content, _ := tools.ReadSlides("talk.md"); content != before {
+		t.Errorf("deck written before review:\n%s", content)
+	}
+	if sets, _ := tools.ListChangeSets("talk.md"); len(sets) != 1 || len(sets[0].Hunks) != 2 {
+		t.Errorf("expected one change set with both edits, got %+v", sets)
+	}
+
+	// Tool failures are results the model can read, not protocol errors
+	result, rpcErr := rpc(t, s, "tools/call", map[string]any{"name": "update_page", "arguments": map[string]any{
+		"project": "talk", "page": 42, "markdown": "x",
+	}})
+	if rpcErr != nil || result["isError"] != true {
+		t.Errorf("expected an error result, got %v / %v", result, rpcErr)
+	}
+	if _, rpcErr := rpc(t, s, "tools/call", map[string]any{"name": "format_disk"}); rpcErr == nil || rpcErr.Code != codeInvalidParams {
+		t.Errorf("expected invalid params for an unknown tool, got %v", rpcErr)
+	}
+	if result, _ := rpc(t, s, "tools/call", map[string]any{"name": "read_slides", "arguments": map[string]any{"project": "../etc/passwd"}}); result["isError"] != true {
+		t.Errorf("expected paths outside the workspace to be rejected, got %v", result)
+	}
+
+	result, _ = rpc(t, s, "resources/list", nil)
+	resources := result["resources"].([]any)
+	if len(resources) != 1 || resources[0].(map[string]any)["uri"] != "slidev://projects/talk.md" {
+		t.Fatalf("unexpected resources %v", resources)
+	}
+	result, _ = rpc(t, s, "resources/read", map[string]any{"uri": "slidev://projects/talk.md"})
+	contents := result["contents"].([]any)
+	if text := contents[0].(map[string]any)["text"].(string); !strings.Contains(text, "# Written by an agent") {
+		t.Errorf("unexpected resource text %q", text)
+	}
+	if _, rpcErr := rpc(t, s, "resources/read", map[string]any{"uri": "slidev://projects/missing.md"}); rpcErr == nil || rpcErr.Code != -32002 {
+		t.Errorf("expected resource not found, got %v", rpcErr)
+	}
+	if _, rpcErr := rpc(t, s, "bogus/method", nil); rpcErr == nil || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %v", rpcErr)
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	s, _ := newTestServer(t)
+	in := strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}
+{"jsonrpc": "2.0", "method": "notifications/initialized"}
+
+not json
+[{"jsonrpc": "2.0", "id": 2, "method": "ping"}, {"jsonrpc": "2.0", "id": 3, "method": "tools/list"}]
+`)
+	var out strings.Builder
+	if err := s.ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 replies, got %q", out.String())
+	}
+	if !strings.Contains(lines[1], `"code":-32700`) {
+		t.Errorf("expected a parse error, got %s", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "[") {
+		t.Errorf("expected a batch reply, got %s", lines[2])
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	s, _ := newTestServer(t)
+	post := func(body, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(body))
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`, "http://localhost:5173"); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("ping: %d %s", rec.Code, rec.Body)
+	}
+	if rec := post(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`, ""); rec.Code != http.StatusAccepted {
+		t.Errorf("expected 202 for a notification, got %d", rec.Code)
+	}
+	if rec := post(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`, "https://attacker.example"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a foreign origin, got %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/mcp", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
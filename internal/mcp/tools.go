@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+type toolDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// toolDefs are the deck tools offered to agents. Each maps onto a Tools method.
+var toolDefs = []toolDef{
+	{
+		Name:        "list_projects",
+		Description: "List the decks in the workspace.",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+	},
+	{
+		Name:        "read_slides",
+		Description: "Read the full Slidev markdown of a deck.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {"project": {"type": "string", "description": "Deck file name, e.g. talk.md"}},
+			"required": ["project"]
+		}`),
+	},
+	{
+		Name:        "update_page",
+		Description: "Propose replacing the markdown of one page. The user reviews the change before it is written; updates in a row are reviewed together. Pages are numbered from 0 as Slidev shows them; a page's frontmatter is kept.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"project": {"type": "string", "description": "Deck file name, e.g. talk.md"},
+				"page": {"type": "integer", "minimum": 0, "description": "Index of the page to replace"},
+				"markdown": {"type": "string", "description": "New page content, without frontmatter or --- lines"},
+				"summary": {"type": "string", "description": "What the change is for, shown to the user"}
+			},
+			"required": ["project", "page", "markdown"]
+		}`),
+	},
+	{
+		Name:        "insert_page",
+		Description: "Propose inserting an empty page after the given page. The user reviews the change before it is written; edits in a row are reviewed together and count the pages they add, so update_page can fill in the new page.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"project": {"type": "string", "description": "Deck file name, e.g. talk.md"},
+				"after": {"type": "integer", "minimum": -1, "description": "Index of the page to insert after, -1 to insert first"},
+				"layout": {"type": "string", "description": "Slidev layout of the new page, e.g. center"},
+				"summary": {"type": "string", "description": "What the change is for, shown to the user"}
+			},
+			"required": ["project", "after"]
+		}`),
+	},
+	{
+		Name:        "apply_theme",
+		Description: "Propose setting the Slidev theme of the whole deck. The user reviews the change before it is written.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"project": {"type": "string", "description": "Deck file name, e.g. talk.md"},
+				"theme": {"type": "string", "description": "Theme name, e.g. seriph"},
+				"summary": {"type": "string", "description": "What the change is for, shown to the user"}
+			},
+			"required": ["project", "theme"]
+		}`),
+	},
+}
+
+type toolArgs struct {
+	Project  string `json:"project"`
+	Page     int    `json:"page"`
+	Markdown string `json:"markdown"`
+	After    int    `json:"after"`
+	Layout   string `json:"layout"`
+	Theme    string `json:"theme"`
+	Summary  string `json:"summary"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+func textResult(text string) toolResult {
+	return toolResult{Content: []content{{Type: "text", Text: text}}}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string   `json:"name"`
+		Arguments toolArgs `json:"arguments"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	text, err := s.runTool(p.Name, p.Arguments)
+	if err != nil {
+		if rpcErr, ok := err.(*rpcError); ok {
+			return nil, rpcErr
+		}
+		// Tool failures are reported to the model rather than as protocol errors
+		return toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return textResult(text), nil
+}
+
+func (s *Server) runTool(name string, args toolArgs) (string, error) {
+	if name == "list_projects" {
+		projects, err := s.Tools.ListProjects()
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		for _, p := range projects {
+			fmt.Fprintf(&b, "%s\t%s\n", p.Name, p.Updated)
+		}
+		if b.Len() == 0 {
+			return "The workspace has no decks.", nil
+		}
+		return b.String(), nil
+	}
+
+	if !hasTool(name) {
+		return "", errorf(codeInvalidParams, "unknown tool %q", name)
+	}
+	project, err := deckName(args.Project)
+	if err != nil {
+		return "", err
+	}
+	switch name {
+	case "read_slides":
+		return s.Tools.ReadSlides(project)
+	case "update_page":
+		cs, err := s.Tools.ProposePageUpdate(project, args.Page, args.Markdown, args.Summary)
+		return proposed(cs, err, fmt.Sprintf("page %d of %s", args.Page, project))
+	case "insert_page":
+		cs, err := s.Tools.ProposePageInsert(project, args.After, args.Layout, args.Summary)
+		return proposed(cs, err, fmt.Sprintf("a page after page %d of %s", args.After, project))
+	default: // apply_theme
+		if args.Theme == "" {
+			return "", fmt.Errorf("theme is required")
+		}
+		cs, err := s.Tools.ProposeGlobalTheme(project, args.Theme, args.Summary)
+		return proposed(cs, err, fmt.Sprintf("theme %s for %s", args.Theme, project))
+	}
+}
+
+// proposed reports the change set a tool proposed for what
+func proposed(cs *slidev.ChangeSet, err error, what string) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if cs == nil {
+		return fmt.Sprintf("Nothing to change for %s.", what), nil
+	}
+	data, err := json.MarshalIndent(cs, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Proposed change set %s for %s. It is written once the user accepts it.\n%s", cs.ID, what, data), nil
+}
+
+func hasTool(name string) bool {
+	for _, t := range toolDefs {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deckName validates a deck name from an agent: a deck file directly in the
+// workspace. The .md extension is optional.
+func deckName(raw string) (string, error) {
+	name := raw
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+	if raw == "" || filepath.Base(name) != name || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid deck name %q", raw)
+	}
+	return name, nil
+}
+
+// Resources
+
+// resourceScheme prefixes the URIs of decks exposed as resources
+const resourceScheme = "slidev://projects/"
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+var deckTemplate = resourceTemplate{
+	URITemplate: resourceScheme + "{name}",
+	Name:        "deck",
+	Description: "Slidev markdown of a deck in the workspace",
+	MimeType:    "text/markdown",
+}
+
+func (s *Server) listResources() (any, error) {
+	projects, err := s.Tools.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	resources := []resource{}
+	for _, p := range projects {
+		resources = append(resources, resource{
+			URI:         resourceScheme + url.PathEscape(p.Name),
+			Name:        p.Name,
+			Description: "Updated " + p.Updated,
+			MimeType:    "text/markdown",
+		})
+	}
+	return map[string]any{"resources": resources}, nil
+}
+
+func (s *Server) readResource(params json.RawMessage) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	escaped, ok := strings.CutPrefix(p.URI, resourceScheme)
+	if !ok {
+		return nil, errorf(codeInvalidParams, "unknown resource %q", p.URI)
+	}
+	raw, err := url.PathUnescape(escaped)
+	if err != nil {
+		return nil, errorf(codeInvalidParams, "unknown resource %q", p.URI)
+	}
+	name, err := deckName(raw)
+	if err != nil {
+		return nil, errorf(codeInvalidParams, "%v", err)
+	}
+	text, err := s.Tools.ReadSlides(name)
+	if err != nil {
+		// -32002 is the code MCP reserves for missing resources
+		return nil, errorf(-32002, "resource %q not found", p.URI)
+	}
+	return map[string]any{"contents": []map[string]string{
+		{"uri": p.URI, "mimeType": "text/markdown", "text": text},
+	}}, nil
+}
@@ -0,0 +1,370 @@
+// Package search keeps a full-text index over the decks of the workspace.
+package search
+
+import (
+	"html"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+// Result is a slide matching a query
+type Result struct {
+	Project string  `json:"project"` // Deck file name, e.g. "talks/q3.md"
+	Slide   int     `json:"slide"`   // 0-based slide index
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"` // HTML with matches wrapped in <mark>
+	Score   float64 `json:"score"`
+}
+
+// Text fields of a slide and the weight of a match in them
+var fieldWeights = map[string]float64{
+	"title":       3,
+	"body":        1,
+	"notes":       0.5,
+	"frontmatter": 0.5,
+}
+
+// Fields searched by terms without a field, in the order snippets prefer
+var textFields = []string{"body", "notes", "title", "frontmatter"}
+
+// snippetLength is the approximate length of a snippet in characters
+const snippetLength = 160
+
+// Index is a full-text index over the slides of all decks in the workspace.
+// It refreshes itself before each search, re-reading decks whose size or
+// modification time changed, so it also sees edits made outside the app.
+type Index struct {
+	tools *slidev.Tools
+
+	mu    sync.Mutex
+	dir   string           // Workspace the files were read from
+	files map[string]*file // By deck file name
+	stale map[string]bool  // Decks written since the last refresh
+}
+
+// file is an indexed deck
+type file struct {
+	modTime time.Time
+	size    int64
+	slides  []*doc
+}
+
+// doc is an indexed slide
+type doc struct {
+	slide  int
+	title  string
+	layout string            // Effective layout, e.g. "cover" for a first slide without one
+	meta   map[string]string // Lowercased frontmatter values, the headmatter's as defaults
+	fields map[string]*field
+}
+
+// field is the text of a slide field with its words
+type field struct {
+	text  string // Whitespace collapsed
+	words []word
+}
+
+// word is a token of a field and its byte span in the text
+type word struct {
+	token      string
+	start, end int
+}
+
+// NewIndex returns an index over the decks of tools. It is built on the
+// first search.
+func NewIndex(tools *slidev.Tools) *Index {
+	return &Index{tools: tools, files: map[string]*file{}, stale: map[string]bool{}}
+}
+
+// Invalidate marks a deck as changed so the next search re-reads it even if
+// its size and modification time look the same. It is meant for
+// slidev.Tools.OnChange.
+func (x *Index) Invalidate(filename string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.stale[filename] = true
+}
+
+// refresh re-indexes the decks that changed since the last search and drops
+// the ones that are gone
+func (x *Index) refresh() error {
+	dir := x.tools.Dir()
+	projects, err := x.tools.ListProjects()
+	if err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if dir != x.dir {
+		x.dir, x.files = dir, map[string]*file{}
+	}
+	seen := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		seen[p.Name] = true
+		path, err := slidev.ResolvePath(dir, p.Name)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Deleted since it was listed
+		}
+		if f := x.files[p.Name]; f != nil && !x.stale[p.Name] && f.size == info.Size() && f.modTime.Equal(info.ModTime()) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		x.files[p.Name] = &file{modTime: info.ModTime(), size: info.Size(), slides: indexDeck(string(data))}
+	}
+	for name := range x.files {
+		if !seen[name] {
+			delete(x.files, name)
+		}
+	}
+	x.stale = map[string]bool{}
+	return nil
+}
+
+// indexDeck splits a deck into slide documents
+func indexDeck(content string) []*doc {
+	deck := slidev.ParseDeck(content)
+	headmatter := map[string]string{}
+	if len(deck.Slides) > 0 {
+		headmatter = deck.Slides[0].Fields()
+	}
+	docs := make([]*doc, len(deck.Slides))
+	for i, s := range deck.Slides {
+		d := &doc{slide: i, title: s.Title(), layout: s.Layout(), meta: map[string]string{}}
+		if d.layout == "" {
+			d.layout = "default"
+			if i == 0 {
+				d.layout = "cover"
+			}
+		}
+		for k, v := range headmatter {
+			d.meta[strings.ToLower(k)] = strings.ToLower(v)
+		}
+		for k, v := range s.Fields() {
+			d.meta[strings.ToLower(k)] = strings.ToLower(v)
+		}
+		d.meta["layout"] = strings.ToLower(d.layout)
+		d.fields = map[string]*field{
+			"title":       newField(d.title),
+			"body":        newField(s.Body()),
+			"notes":       newField(s.Notes()),
+			"frontmatter": newField(s.Frontmatter),
+		}
+		docs[i] = d
+	}
+	return docs
+}
+
+func newField(text string) *field {
+	f := &field{text: strings.Join(strings.Fields(text), " ")}
+	for _, span := range wordSpans(f.text) {
+		f.words = append(f.words, word{token: strings.ToLower(f.text[span[0]:span[1]]), start: span[0], end: span[1]})
+	}
+	return f
+}
+
+// Search returns the slides matching query, best first, at most limit of
+// them when limit is positive. All terms of a query must match. Words match
+// words starting with them, "quoted text" matches a phrase, and field:value
+// limits a term to the title, body, notes, frontmatter or project name of a
+// slide. layout:name and other frontmatter keys, e.g. theme:seriph, match
+// the slide's value of that key exactly.
+func (x *Index) Search(query string, limit int) ([]Result, error) {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if err := x.refresh(); err != nil {
+		return nil, err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var results []Result
+	for name, f := range x.files {
+		project := newField(name)
+		for _, d := range f.slides {
+			score, spans, ok := d.match(terms, project)
+			if !ok {
+				continue
+			}
+			results = append(results, Result{
+				Project: name,
+				Slide:   d.slide,
+				Title:   d.title,
+				Snippet: d.snippet(spans),
+				Score:   score,
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.Slide < b.Slide
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// match scores a slide against all terms, returning the matched word spans
+// of each text field for the snippet
+func (d *doc) match(terms []term, project *field) (float64, map[string][][2]int, bool) {
+	var score float64
+	spans := map[string][][2]int{}
+	for _, t := range terms {
+		var fields []string
+		switch {
+		case t.field == "":
+			fields = textFields
+		case t.field == "project":
+			if s, _ := project.find(t); s == 0 {
+				return 0, nil, false
+			}
+			continue
+		case fieldWeights[t.field] > 0:
+			fields = []string{t.field}
+		default:
+			// A frontmatter key such as layout: compared as a whole
+			if v, ok := d.meta[t.field]; !ok || v != t.value {
+				return 0, nil, false
+			}
+			continue
+		}
+
+		var termScore float64
+		for _, name := range fields {
+			s, found := d.fields[name].find(t)
+			termScore += s * fieldWeights[name]
+			spans[name] = append(spans[name], found...)
+		}
+		if termScore == 0 {
+			return 0, nil, false
+		}
+		score += termScore
+	}
+	return score, spans, true
+}
+
+// find returns how well a term matches the field, counting exact words once
+// and words the term is a prefix of half, with the byte spans matched
+func (f *field) find(t term) (float64, [][2]int) {
+	if len(t.tokens) == 0 {
+		return 0, nil
+	}
+	var score float64
+	var spans [][2]int
+	for i := range f.words {
+		if !t.phrase {
+			switch w := f.words[i]; {
+			case w.token == t.tokens[0]:
+				score++
+			case strings.HasPrefix(w.token, t.tokens[0]):
+				score += 0.5
+			default:
+				continue
+			}
+			spans = append(spans, [2]int{f.words[i].start, f.words[i].end})
+			continue
+		}
+		if i+len(t.tokens) > len(f.words) {
+			break
+		}
+		matched := true
+		for j, token := range t.tokens {
+			if f.words[i+j].token != token {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			// Phrases are rarer than words and count double
+			score += 2
+			spans = append(spans, [2]int{f.words[i].start, f.words[i+len(t.tokens)-1].end})
+		}
+	}
+	return score, spans
+}
+
+// snippet returns an excerpt of the first field with a match, preferring the
+// body, with the matches marked. Slides matched by filters alone show the
+// start of their body.
+func (d *doc) snippet(spans map[string][][2]int) string {
+	name := "body"
+	for _, n := range textFields {
+		if len(spans[n]) > 0 {
+			name = n
+			break
+		}
+	}
+	text, marks := d.fields[name].text, spans[name]
+	sort.Slice(marks, func(i, j int) bool { return marks[i][0] < marks[j][0] })
+
+	start, end := 0, len(text)
+	if len(text) > snippetLength {
+		if len(marks) > 0 {
+			start = max(0, marks[0][0]-snippetLength/3)
+		}
+		end = min(len(text), start+snippetLength)
+		start, end = wordBoundary(text, start, false), wordBoundary(text, end, true)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range marks {
+		if m[0] < pos || m[1] > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// wordBoundary moves i to the nearest space so snippets don't cut words,
+// forward when forward is set, staying on a rune boundary otherwise
+func wordBoundary(text string, i int, forward bool) int {
+	if i <= 0 || i >= len(text) {
+		return i
+	}
+	if forward {
+		if j := strings.IndexByte(text[i:], ' '); j >= 0 && j < snippetLength/4 {
+			return i + j
+		}
+	} else if j := strings.LastIndexByte(text[:i], ' '); j >= 0 && i-j < snippetLength/4 {
+		return j + 1
+	}
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
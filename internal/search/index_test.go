@@ -0,0 +1,133 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"slidev-studio-ai/internal/slidev"
+)
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	decks := map[string]string{
+		"q3.md":          "---\ntheme: seriph\n---\n\n# Q3 Review\n\nA quarter of growth\n\n---\nlayout: two-cols\n---\n\n# Revenue\n\nRevenue chart for <EMEA>\n\n::right::\n\n![chart](./chart.png)\n\n<!--\nStress the revenue growth in EMEA\n-->\n",
+		"talks/intro.md": "# Introduction\n\nWhy we track revenue at all\n\n---\n\n# Roadmap\n\nRevenues grow with the roadmap\n",
+	}
+	for name, content := range decks {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tools := slidev.NewTools(root)
+	index := NewIndex(tools)
+	tools.OnChange = index.Invalidate
+
+	hits := func(query string) []string {
+		t.Helper()
+		results, err := index.Search(query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, fmt.Sprintf("%s#%d", r.Project, r.Slide))
+		}
+		return got
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// A title match outweighs body text, exact words outweigh prefixes
+		{"revenue", []string{"q3.md#1", "talks/intro.md#0", "talks/intro.md#1"}},
+		{"REVENUE emea", []string{"q3.md#1"}},
+		{`"revenue growth"`, []string{"q3.md#1"}},
+		{`"growth revenue"`, nil},
+		{"notes:stress", []string{"q3.md#1"}},
+		{"body:stress", nil},
+		{"title:roadmap", []string{"talks/intro.md#1"}},
+		{"layout:two-cols", []string{"q3.md#1"}},
+		{"layout:cover", []string{"q3.md#0", "talks/intro.md#0"}},
+		{"layout:default revenue", []string{"talks/intro.md#1"}},
+		{"theme:seriph quarter", []string{"q3.md#0"}},
+		{"project:talks grow", []string{"talks/intro.md#1"}},
+		{"missing", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := hits(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	results, _ := index.Search("emea", 0)
+	if len(results) != 1 || results[0].Title != "Revenue" || !strings.Contains(results[0].Snippet, "chart for &lt;<mark>EMEA</mark>&gt;") {
+		t.Errorf("unexpected result %+v", results)
+	}
+
+	// Saving through the tools updates the index, as do deletions
+	if err := tools.SaveSlides("talks/intro.md", "# Introduction\n\nNothing about money\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := hits("revenue"); !reflect.DeepEqual(got, []string{"q3.md#1"}) {
+		t.Errorf("after saving: %v", got)
+	}
+	if err := tools.DeleteProject("q3.md"); err != nil {
+		t.Fatal(err)
+	}
+	if got := hits("revenue"); got != nil {
+		t.Errorf("after deleting: %v", got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	got := parseQuery(`Q3 "net revenue" layout:two-cols title:"big  news" ! notes:`)
+	want := []term{
+		{value: "q3", tokens: []string{"q3"}},
+		{value: "net revenue", tokens: []string{"net", "revenue"}, phrase: true},
+		{field: "layout", value: "two-cols", tokens: []string{"two", "cols"}, phrase: true},
+		{field: "title", value: "big  news", tokens: []string{"big", "news"}, phrase: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQuery = %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchCJK(t *testing.T) {
+	root := t.TempDir()
+	deck := "# 第三季度收入图表\n\nQ3收入增长来自华东\n\n---\n\n# 路线图\n\n收益与支出\n"
+	if err := os.WriteFile(filepath.Join(root, "季报.md"), []byte(deck), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index := NewIndex(slidev.NewTools(root))
+
+	for query, want := range map[string]int{
+		"收入":            1,
+		"收入图表":          1,
+		"图表收入":          0,
+		"title:季度":      1,
+		"q3收入":          1,
+		"收":             2,
+		"project:季报 路线": 1,
+	} {
+		results, err := index.Search(query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != want {
+			t.Errorf("Search(%q) = %+v, want %d results", query, results, want)
+		}
+	}
+
+	results, _ := index.Search("收入", 0)
+	if len(results) != 1 || !strings.Contains(results[0].Snippet, "Q3<mark>收入</mark>增长") {
+		t.Errorf("unexpected result %+v", results)
+	}
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// term is one condition of a query: a word or a phrase, optionally limited
+// to a field
+type term struct {
+	field  string   // "" for any text field
+	value  string   // Lowercased value as written
+	tokens []string // Tokens of value
+	phrase bool     // Quoted: the tokens must appear in order
+}
+
+// parseQuery splits a query into terms. Words are separated by spaces,
+// "quoted text" is a phrase and field:value or field:"quoted text" limits a
+// term to a field, e.g. title:revenue or layout:two-cols.
+func parseQuery(query string) []term {
+	var terms []term
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		var t term
+		if i := strings.IndexAny(rest, ": \""); i > 0 && rest[i] == ':' && isFieldName(rest[:i]) {
+			t.field = strings.ToLower(rest[:i])
+			rest = rest[i+1:]
+		}
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			t.value, t.phrase = rest[1:end+1], true
+			rest = rest[min(end+2, len(rest)):]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			t.value, rest = rest[:end], rest[end:]
+		}
+		rest = strings.TrimSpace(rest)
+
+		t.value = strings.ToLower(strings.TrimSpace(t.value))
+		t.tokens = tokenize(t.value)
+		if t.value == "" || t.field == "" && len(t.tokens) == 0 {
+			continue // Punctuation or an empty phrase
+		}
+		if len(t.tokens) > 1 {
+			// A word such as "q3-revenue" is matched like the phrase "q3 revenue"
+			t.phrase = true
+		}
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+func isFieldName(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize lowercases text and splits it into words, see wordSpans
+func tokenize(text string) []string {
+	var tokens []string
+	for _, span := range wordSpans(text) {
+		tokens = append(tokens, strings.ToLower(text[span[0]:span[1]]))
+	}
+	return tokens
+}
+
+// wordSpans returns the byte spans of the words of text: runs of letters and
+// digits, except that Chinese, Japanese and Korean characters are words of
+// their own. Those scripts do not separate words with spaces, so a query
+// such as 收入 is matched as the phrase of its characters.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		if start >= 0 && (isCJK(r) || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+		switch {
+		case isCJK(r):
+			spans = append(spans, [2]int{i, i + utf8.RuneLen(r)})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,217 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	fileVersion      = 1
+	kdfPBKDF2        = "pbkdf2-sha256"
+	kdfKeyFile       = "keyfile"
+	pbkdf2Iterations = 600000
+)
+
+// additionalData binds ciphertexts to this file format
+var additionalData = []byte("slidev-studio-ai secrets v1")
+
+// FileStore is an AES-GCM encrypted secrets file. The key is derived from a
+// passphrase when one is given, otherwise a random key is kept next to the
+// store with owner-only permissions, which works on headless machines.
+type FileStore struct {
+	path       string
+	keyPath    string
+	passphrase string
+	mu         sync.Mutex
+}
+
+type storeFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func NewFileStore(dir, passphrase string) *FileStore {
+	return &FileStore{
+		path:       filepath.Join(dir, "secrets.enc"),
+		keyPath:    filepath.Join(dir, "secrets.key"),
+		passphrase: passphrase,
+	}
+}
+
+func (f *FileStore) Get(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (f *FileStore) Set(key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.read()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+	return f.write(values)
+}
+
+func (f *FileStore) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+	delete(values, key)
+	return f.write(values)
+}
+
+func (f *FileStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sf storeFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+	if sf.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", sf.Version)
+	}
+	key, err := f.key(sf.KDF, sf.Salt, sf.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, sf.Nonce, sf.Data, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets: wrong passphrase or corrupted file")
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("invalid secrets payload: %w", err)
+	}
+	return values, nil
+}
+
+func (f *FileStore) write(values map[string]string) error {
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	sf := storeFile{Version: fileVersion, KDF: kdfKeyFile}
+	if f.passphrase != "" {
+		sf.KDF = kdfPBKDF2
+		sf.Iterations = pbkdf2Iterations
+		sf.Salt = make([]byte, 16)
+		if _, err := rand.Read(sf.Salt); err != nil {
+			return err
+		}
+	}
+	key, err := f.key(sf.KDF, sf.Salt, sf.Iterations)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	sf.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(sf.Nonce); err != nil {
+		return err
+	}
+	sf.Data = aead.Seal(nil, sf.Nonce, plain, additionalData)
+
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, data, 0600)
+}
+
+// key returns the encryption key for the given derivation method
+func (f *FileStore) key(kdf string, salt []byte, iterations int) ([]byte, error) {
+	switch kdf {
+	case kdfPBKDF2:
+		if f.passphrase == "" {
+			return nil, fmt.Errorf("secrets file is passphrase protected; set %s", PassphraseEnv)
+		}
+		return pbkdf2.Key(sha256.New, f.passphrase, salt, iterations, 32)
+	case kdfKeyFile:
+		return f.loadOrCreateKeyFile()
+	default:
+		return nil, fmt.Errorf("unknown key derivation %q", kdf)
+	}
+}
+
+func (f *FileStore) loadOrCreateKeyFile() ([]byte, error) {
+	key, err := os.ReadFile(f.keyPath)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, errors.New("invalid secrets key file")
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.keyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(f.keyPath, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		dir := t.TempDir()
+		store := NewFileStore(dir, passphrase)
+
+		if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if err := store.Set("provider/default", "sk-secret"); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+
+		data, err := os.ReadFile(store.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "sk-secret") {
+			t.Fatalf("secret stored in clear text")
+		}
+
+		// A fresh store over the same directory can read the value back
+		if got, err := NewFileStore(dir, passphrase).Get("provider/default"); err != nil || got != "sk-secret" {
+			t.Fatalf("expected sk-secret, got %q (%v)", got, err)
+		}
+		if err := store.Delete("provider/default"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := store.Get("provider/default"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound after delete, got %v", err)
+		}
+	}
+
+	// A passphrase-protected store cannot be opened with the wrong passphrase
+	dir := t.TempDir()
+	if err := NewFileStore(dir, "right").Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(dir, "wrong").Get("k"); err == nil {
+		t.Fatalf("expected decryption error with wrong passphrase")
+	}
+}
@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keyring stores secrets in the macOS login keychain via the security CLI
+type keyring struct{}
+
+// errItemNotFound is the exit status security uses for a missing item
+const errItemNotFound = 44
+
+func keyringAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func (keyring) Get(key string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", serviceName, "-a", key, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errItemNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("keychain lookup failed: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (keyring) Set(key, value string) error {
+	// Send the command on stdin so the secret never shows up in the process list
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		quote(serviceName), quote(key), quote(value)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain store failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (keyring) Delete(key string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", serviceName, "-a", key).Run()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == errItemNotFound) {
+		return fmt.Errorf("keychain delete failed: %w", err)
+	}
+	return nil
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
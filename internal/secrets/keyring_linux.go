@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// keyring stores secrets in the freedesktop Secret Service (GNOME Keyring,
+// KWallet) via secret-tool
+type keyring struct{}
+
+// keyringAvailable reports whether secret-tool is installed and a session bus
+// is reachable; headless machines use the file store instead
+func keyringAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (keyring) Get(key string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", serviceName, "account", key).Output()
+	if err != nil {
+		// secret-tool exits with 1 and no output when nothing matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(out) == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secret service lookup failed: %w", err)
+	}
+	return string(out), nil
+}
+
+func (keyring) Set(key, value string) error {
+	cmd := exec.Command("secret-tool", "store", "--label="+serviceName+" "+key, "service", serviceName, "account", key)
+	cmd.Stdin = strings.NewReader(value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret service store failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (keyring) Delete(key string) error {
+	if err := exec.Command("secret-tool", "clear", "service", serviceName, "account", key).Run(); err != nil {
+		return fmt.Errorf("secret service delete failed: %w", err)
+	}
+	return nil
+}
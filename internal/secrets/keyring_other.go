@@ -0,0 +1,18 @@
+//go:build !darwin && !linux
+
+package secrets
+
+import "errors"
+
+// keyring is not implemented on this platform; Open uses the file store
+type keyring struct{}
+
+var errNoKeyring = errors.New("OS keyring is not supported on this platform")
+
+func keyringAvailable() bool { return false }
+
+func (keyring) Get(key string) (string, error) { return "", errNoKeyring }
+
+func (keyring) Set(key, value string) error { return errNoKeyring }
+
+func (keyring) Delete(key string) error { return errNoKeyring }
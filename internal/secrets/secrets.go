@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+)
+
+const (
+	// BackendEnv forces a backend: "keyring" or "file"
+	BackendEnv = "SLIDEV_AI_SECRET_BACKEND"
+	// PassphraseEnv, when set, is used to derive the file store encryption key
+	PassphraseEnv = "SLIDEV_AI_PASSPHRASE"
+
+	serviceName = "slidev-studio-ai"
+)
+
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps secret values (API keys) outside of the plain-text config
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// Open returns the OS keyring when one is usable and falls back to an
+// encrypted file store in dir otherwise
+func Open(dir string) Store {
+	switch os.Getenv(BackendEnv) {
+	case "file":
+		return NewFileStore(dir, os.Getenv(PassphraseEnv))
+	case "keyring":
+		return keyring{}
+	}
+	if keyringAvailable() {
+		return keyring{}
+	}
+	return NewFileStore(dir, os.Getenv(PassphraseEnv))
+}
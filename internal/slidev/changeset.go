@@ -0,0 +1,491 @@
+package slidev
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrChangeSetNotFound is returned for change set IDs that do not exist
+var ErrChangeSetNotFound = errors.New("change set not found")
+
+// ErrChangeSetStale is returned when applying a change set to a deck that
+// was edited after the change was proposed
+var ErrChangeSetStale = errors.New("deck changed since the change set was proposed")
+
+// HunkKind says what a hunk does to a slide
+type HunkKind string
+
+const (
+	HunkAdded    HunkKind = "added"
+	HunkRemoved  HunkKind = "removed"
+	HunkModified HunkKind = "modified"
+)
+
+// ChangeSet is an edit proposed for a deck, e.g. by the AI, split into
+// per-slide hunks the user accepts or rejects individually
+type ChangeSet struct {
+	ID      string    `json:"id"`
+	File    string    `json:"file"`
+	Summary string    `json:"summary"` // What the change is for, as given by its author
+	Created time.Time `json:"created"`
+	Hunks   []Hunk    `json:"hunks"`
+}
+
+// Hunk is the change of one slide
+type Hunk struct {
+	ID          int           `json:"id"`
+	Kind        HunkKind      `json:"kind"`
+	Slide       int           `json:"slide"`    // Index in the current deck; -1 for added slides
+	NewSlide    int           `json:"newSlide"` // Index in the proposed deck; -1 for removed slides
+	Title       string        `json:"title"`
+	Old         string        `json:"old"` // Markdown of the slide, "" for added slides
+	New         string        `json:"new"` // Markdown of the slide, "" for removed slides
+	Frontmatter []FieldChange `json:"frontmatter,omitempty"`
+	Words       []WordChange  `json:"words,omitempty"` // Word-level diff of the content of modified slides
+}
+
+// FieldChange is a changed frontmatter key. Old is "" for added keys and New
+// for removed ones.
+type FieldChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// WordChange is a run of text a diff keeps, removes or inserts
+type WordChange struct {
+	Op   string `json:"op"` // "=", "-" or "+"
+	Text string `json:"text"`
+}
+
+// changeRecord is how a change set is stored: with the deck it was made
+// against and the proposed content, from which hunks are applied
+type changeRecord struct {
+	ChangeSet
+	Base     string `json:"base"`
+	Proposed string `json:"proposed"`
+}
+
+// changesDir holds a deck's pending change sets. The caller must hold t.mu.
+func (t *Tools) changesDir(filename string) string {
+	return filepath.Join(t.deckDataDir(filename), "changes")
+}
+
+// ProposeChange records proposed as a change set for a deck rather than
+// writing it. It returns nil when proposed matches the deck.
+func (t *Tools) ProposeChange(filename, proposed, summary string) (*ChangeSet, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, err := t.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.proposeLocked(filename, string(data), proposed, summary)
+}
+
+// ProposePageUpdate is UpdatePage as a change set: it proposes replacing the
+// content of a page, keeping its frontmatter. Pages are numbered as in
+// UpdatePage, counting the pages of the pending proposal, see proposeEdit.
+func (t *Tools) ProposePageUpdate(filename string, pageIndex int, markdown, summary string) (*ChangeSet, error) {
+	return t.proposeEdit(filename, summary, func(deck *Deck) error {
+		return setPageContent(deck, pageIndex, markdown)
+	})
+}
+
+// ProposePageInsert is InsertPage as a change set, see ProposePageUpdate
+func (t *Tools) ProposePageInsert(filename string, afterIndex int, layout, summary string) (*ChangeSet, error) {
+	return t.proposeEdit(filename, summary, func(deck *Deck) error {
+		insertPage(deck, afterIndex, layout)
+		return nil
+	})
+}
+
+// ProposeGlobalTheme is ApplyGlobalTheme as a change set, see
+// ProposePageUpdate
+func (t *Tools) ProposeGlobalTheme(filename, themeName, summary string) (*ChangeSet, error) {
+	return t.proposeEdit(filename, summary, func(deck *Deck) error {
+		return setTheme(deck, themeName)
+	})
+}
+
+// proposeEdit proposes the deck edit makes as a change set. While the deck
+// is unchanged, further edits extend the latest pending change set and apply
+// to the deck it proposes, so that the edits of one AI reply are reviewed
+// together and an inserted page can be filled in by the next edit.
+func (t *Tools) proposeEdit(filename, summary string, edit func(deck *Deck) error) (*ChangeSet, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	base, prev, err := t.pendingLocked(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := ParseDeck(base)
+	if prev != nil {
+		deck = ParseDeck(prev.Proposed)
+	}
+	if err := edit(deck); err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		return t.proposeLocked(filename, base, deck.String(), summary)
+	}
+	prev.Proposed = deck.String()
+	prev.Hunks = diffDecks(ParseDeck(base), deck)
+	if summary != "" && !strings.Contains(prev.Summary, summary) {
+		prev.Summary = strings.TrimPrefix(prev.Summary+"; "+summary, "; ")
+	}
+	if err := t.writeChangeSet(filename, prev); err != nil {
+		return nil, err
+	}
+	return &prev.ChangeSet, nil
+}
+
+// pendingLocked reads the deck and the latest pending change set that
+// further edits extend, nil when the deck changed since it was proposed.
+// The caller must hold t.mu.
+func (t *Tools) pendingLocked(filename string) (string, *changeRecord, error) {
+	path, err := t.resolve(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	records, err := t.changeRecords(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	if n := len(records); n > 0 && records[n-1].Base == string(data) {
+		return string(data), records[n-1], nil
+	}
+	return string(data), nil, nil
+}
+
+// PendingContent returns the deck the next proposed edit applies to: as the
+// pending change set it extends proposes it, or the deck itself when there
+// is none. Pages of proposed edits are numbered in it.
+func (t *Tools) PendingContent(filename string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	base, prev, err := t.pendingLocked(filename)
+	if err != nil {
+		return "", err
+	}
+	if prev != nil {
+		return prev.Proposed, nil
+	}
+	return base, nil
+}
+
+func (t *Tools) proposeLocked(filename, base, proposed, summary string) (*ChangeSet, error) {
+	hunks := diffDecks(ParseDeck(base), ParseDeck(proposed))
+	if len(hunks) == 0 {
+		return nil, nil
+	}
+	now := time.Now()
+	id, err := newEntryID(now)
+	if err != nil {
+		return nil, err
+	}
+	rec := &changeRecord{
+		ChangeSet: ChangeSet{ID: id, File: filename, Summary: summary, Created: now, Hunks: hunks},
+		Base:      base,
+		Proposed:  proposed,
+	}
+	if err := t.writeChangeSet(filename, rec); err != nil {
+		return nil, err
+	}
+	return &rec.ChangeSet, nil
+}
+
+// writeChangeSet stores a change set. The caller must hold t.mu.
+func (t *Tools) writeChangeSet(filename string, rec *changeRecord) error {
+	dir := t.changesDir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, rec.ID+".json"), data, 0644)
+}
+
+// ListChangeSets returns the pending change sets of a deck, oldest first
+func (t *Tools) ListChangeSets(filename string) ([]ChangeSet, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.resolve(filename); err != nil {
+		return nil, err
+	}
+	records, err := t.changeRecords(filename)
+	if err != nil {
+		return nil, err
+	}
+	sets := []ChangeSet{}
+	for _, rec := range records {
+		sets = append(sets, rec.ChangeSet)
+	}
+	return sets, nil
+}
+
+// changeRecords loads the pending change sets of a deck, oldest first. The
+// caller must hold t.mu.
+func (t *Tools) changeRecords(filename string) ([]*changeRecord, error) {
+	entries, err := os.ReadDir(t.changesDir(filename))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var records []*changeRecord
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || !entryIDPattern.MatchString(id) {
+			continue
+		}
+		rec, err := t.readChangeSet(filename, id)
+		if err != nil {
+			continue // Skip sets that can't be read rather than hide the rest
+		}
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Created.Before(records[j].Created) })
+	return records, nil
+}
+
+// readChangeSet loads a stored change set. The caller must hold t.mu.
+func (t *Tools) readChangeSet(filename, id string) (*changeRecord, error) {
+	if !entryIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: %q", ErrChangeSetNotFound, id)
+	}
+	data, err := os.ReadFile(filepath.Join(t.changesDir(filename), id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrChangeSetNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec changeRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to read change set %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// ApplyChangeSet applies the accepted hunks of a change set to the deck, as
+// one operation Undo reverts, and discards the change set. The other hunks
+// are rejected. It fails with ErrChangeSetStale when the deck changed since
+// the change set was proposed.
+func (t *Tools) ApplyChangeSet(filename, id string, accepted []int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, err := t.resolve(filename)
+	if err != nil {
+		return err
+	}
+	rec, err := t.readChangeSet(filename, id)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if string(data) != rec.Base {
+		return fmt.Errorf("%w: %s", ErrChangeSetStale, id)
+	}
+
+	accept := map[int]bool{}
+	for _, h := range accepted {
+		if h < 0 || h >= len(rec.Hunks) {
+			return fmt.Errorf("change set %s has no hunk %d", id, h)
+		}
+		accept[h] = true
+	}
+	if len(accept) > 0 {
+		after := applyHunks(ParseDeck(rec.Base), ParseDeck(rec.Proposed), accept)
+		label := "Apply proposed changes"
+		if rec.Summary != "" {
+			label += ": " + rec.Summary
+		}
+		if err := t.recordUndo(filename, label, rec.Base, after); err != nil {
+			return fmt.Errorf("failed to record undo: %w", err)
+		}
+		if err := t.writeDeck(filename, path, after); err != nil {
+			return err
+		}
+	}
+	return os.Remove(filepath.Join(t.changesDir(filename), id+".json"))
+}
+
+// DiscardChangeSet rejects a change set as a whole
+func (t *Tools) DiscardChangeSet(filename, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.resolve(filename); err != nil {
+		return err
+	}
+	if _, err := t.readChangeSet(filename, id); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(t.changesDir(filename), id+".json"))
+}
+
+// alignSlides matches the slides of two decks: a diff of their markdown,
+// where a removed slide followed by an added one pairs up as a modification.
+// Trailing blank lines are ignored, as they move when the last slide changes.
+func alignSlides(base, proposed *Deck) []diffOp {
+	var a, b []string
+	for i := range base.Slides {
+		a = append(a, strings.TrimRight(base.slideText(i), " \t\n"))
+	}
+	for i := range proposed.Slides {
+		b = append(b, strings.TrimRight(proposed.slideText(i), " \t\n"))
+	}
+	return diffStrings(a, b)
+}
+
+// diffDecks returns the hunks turning base into proposed
+func diffDecks(base, proposed *Deck) []Hunk {
+	var hunks []Hunk
+	walkAlignment(base, proposed, func(kind HunkKind, i, j int) {
+		if kind == "" {
+			return
+		}
+		h := Hunk{ID: len(hunks), Kind: kind, Slide: i, NewSlide: j}
+		if i >= 0 {
+			h.Old = base.slideText(i)
+			h.Title = base.Slides[i].Title()
+		}
+		if j >= 0 {
+			h.New = proposed.slideText(j)
+			if title := proposed.Slides[j].Title(); title != "" {
+				h.Title = title
+			}
+		}
+		if kind == HunkModified {
+			old, new := base.Slides[i], proposed.Slides[j]
+			h.Frontmatter = diffFields(old, new)
+			if old.Content != new.Content {
+				h.Words = diffWords(old.Content, new.Content)
+			}
+		}
+		hunks = append(hunks, h)
+	})
+	return hunks
+}
+
+// walkAlignment calls fn for each slide of base and proposed, in order,
+// with its index in base and in proposed, -1 where it does not exist. The
+// kind is "" for unchanged slides. Within a run of changes, removed and
+// added slides pair up as modifications.
+func walkAlignment(base, proposed *Deck, fn func(kind HunkKind, i, j int)) {
+	ops := alignSlides(base, proposed)
+	i, j := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k].Kind == ' ' {
+			fn("", i, j)
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+		var removed, added int
+		for ; k < len(ops) && ops[k].Kind != ' '; k++ {
+			if ops[k].Kind == '-' {
+				removed++
+			} else {
+				added++
+			}
+		}
+		for n := 0; n < max(removed, added); n++ {
+			switch {
+			case n < removed && n < added:
+				fn(HunkModified, i+n, j+n)
+			case n < removed:
+				fn(HunkRemoved, i+n, -1)
+			default:
+				fn(HunkAdded, -1, j+n)
+			}
+		}
+		i, j = i+removed, j+added
+	}
+}
+
+// applyHunks builds the deck with only the accepted hunks applied. Hunk IDs
+// follow the order of walkAlignment.
+func applyHunks(base, proposed *Deck, accept map[int]bool) string {
+	out := &Deck{trailingNewline: base.trailingNewline, crlf: base.crlf}
+	id := 0
+	walkAlignment(base, proposed, func(kind HunkKind, i, j int) {
+		if kind == "" {
+			out.Slides = append(out.Slides, proposed.Slides[j])
+			return
+		}
+		taken := accept[id]
+		id++
+		switch {
+		case kind == HunkAdded && taken, kind == HunkModified && taken:
+			out.Slides = append(out.Slides, proposed.Slides[j])
+		case kind == HunkRemoved && !taken, kind == HunkModified && !taken:
+			out.Slides = append(out.Slides, base.Slides[i])
+		}
+	})
+	return out.String()
+}
+
+// diffFields compares the top-level frontmatter keys of two slides. Changes
+// to nested values show as a change of the key holding them.
+func diffFields(old, new Slide) []FieldChange {
+	if old.Frontmatter == new.Frontmatter {
+		return nil
+	}
+	oldFields, newFields := old.Fields(), new.Fields()
+	var changes []FieldChange
+	for k, v := range oldFields {
+		if nv, ok := newFields[k]; !ok || nv != v {
+			changes = append(changes, FieldChange{Key: k, Old: v, New: nv})
+		}
+	}
+	for k, v := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			changes = append(changes, FieldChange{Key: k, New: v})
+		}
+	}
+	if len(changes) == 0 {
+		// Only nested values or comments changed
+		changes = append(changes, FieldChange{Old: old.Frontmatter, New: new.Frontmatter})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+var wordPattern = regexp.MustCompile(`\s+|[^\s]+`)
+
+// diffWords returns a word-level diff of two texts, merging runs of the same
+// operation
+func diffWords(old, new string) []WordChange {
+	var changes []WordChange
+	for _, op := range diffStrings(wordPattern.FindAllString(old, -1), wordPattern.FindAllString(new, -1)) {
+		kind := string(op.Kind)
+		if kind == " " {
+			kind = "="
+		}
+		if n := len(changes); n > 0 && changes[n-1].Op == kind {
+			changes[n-1].Text += op.Text
+			continue
+		}
+		changes = append(changes, WordChange{Op: kind, Text: op.Text})
+	}
+	return changes
+}
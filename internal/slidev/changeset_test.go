@@ -0,0 +1,184 @@
+package slidev
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChangeSets(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	base := "---\ntheme: default\n---\n\n# Intro\n\nHello world\n\n---\n\n# Old slide\n\n---\nlayout: center\n---\n\n# Keep\n\nSame as before\n"
+	proposed := "---\ntheme: seriph\n---\n\n# Intro\n\nHello brave new world\n\n---\nlayout: center\n---\n\n# Keep\n\nSame as before\n\n---\n\n# Added\n"
+	if err := tools.SaveSlides("talk.md", base); err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := tools.ProposeChange("talk.md", proposed, "Polish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); content != base {
+		t.Fatalf("proposing wrote the deck:\n%s", content)
+	}
+
+	var kinds []HunkKind
+	for _, h := range cs.Hunks {
+		kinds = append(kinds, h.Kind)
+	}
+	if want := []HunkKind{HunkModified, HunkRemoved, HunkAdded}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("hunks %v, want %v", kinds, want)
+	}
+	intro := cs.Hunks[0]
+	if intro.Title != "Intro" || !reflect.DeepEqual(intro.Frontmatter, []FieldChange{{Key: "theme", Old: "default", New: "seriph"}}) {
+		t.Errorf("unexpected hunk %+v", intro)
+	}
+	wantWords := []WordChange{{"=", "\n# Intro\n\nHello "}, {"+", "brave new "}, {"=", "world\n\n"}}
+	if !reflect.DeepEqual(intro.Words, wantWords) {
+		t.Errorf("word diff %q, want %q", intro.Words, wantWords)
+	}
+	if cs.Hunks[1].Slide != 1 || cs.Hunks[1].NewSlide != -1 || cs.Hunks[2].Slide != -1 || cs.Hunks[2].NewSlide != 2 {
+		t.Errorf("unexpected slide indexes %+v", cs.Hunks)
+	}
+
+	// Accepting the new slide but rejecting the rest
+	if sets, _ := tools.ListChangeSets("talk.md"); len(sets) != 1 || sets[0].ID != cs.ID {
+		t.Fatalf("ListChangeSets = %+v", sets)
+	}
+	if err := tools.ApplyChangeSet("talk.md", cs.ID, []int{2}); err != nil {
+		t.Fatal(err)
+	}
+	want := base + "\n---\n\n# Added\n"
+	if content, _ := tools.ReadSlides("talk.md"); content != want {
+		t.Errorf("after applying:\n%q\nwant\n%q", content, want)
+	}
+	if sets, _ := tools.ListChangeSets("talk.md"); len(sets) != 0 {
+		t.Errorf("applied change set still pending: %+v", sets)
+	}
+	if _, err := tools.Undo("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	// All hunks give the proposed deck; a deck edited meanwhile refuses
+	cs, _ = tools.ProposeChange("talk.md", proposed, "")
+	if err := tools.ApplyChangeSet("talk.md", cs.ID, []int{0, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); content != proposed {
+		t.Errorf("after applying everything:\n%q", content)
+	}
+	cs, err = tools.ProposePageUpdate("talk.md", 1, "# Centered", "Retitle")
+	if err != nil || len(cs.Hunks) != 1 || cs.Hunks[0].Kind != HunkModified || cs.Hunks[0].Frontmatter != nil {
+		t.Fatalf("ProposePageUpdate = %+v, %v", cs, err)
+	}
+	if err := tools.SetNotes("talk.md", 0, "Edited meanwhile"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.ApplyChangeSet("talk.md", cs.ID, []int{0}); !errors.Is(err, ErrChangeSetStale) {
+		t.Errorf("expected ErrChangeSetStale, got %v", err)
+	}
+	if err := tools.DiscardChangeSet("talk.md", cs.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DiscardChangeSet("talk.md", cs.ID); !errors.Is(err, ErrChangeSetNotFound) {
+		t.Errorf("expected ErrChangeSetNotFound, got %v", err)
+	}
+	if cs, err := tools.ProposeChange("talk.md", mustRead(t, tools, "talk.md"), ""); cs != nil || err != nil {
+		t.Errorf("proposing the same content = %+v, %v", cs, err)
+	}
+}
+
+func mustRead(t *testing.T, tools *Tools, name string) string {
+	t.Helper()
+	content, err := tools.ReadSlides(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestProposePageUpdates(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	base := "---\ntheme: default\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Two\n\n---\n\n# Three\n"
+	if err := tools.SaveSlides("talk.md", base); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pages count a slide with frontmatter once, as UpdatePage does, and the
+	// updates of one reply make up one change set
+	first, err := tools.ProposePageUpdate("talk.md", 1, "# Deux", "Translate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tools.ProposePageUpdate("talk.md", 2, "# Trois", "Translate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID || len(second.Hunks) != 2 || second.Summary != "Translate" {
+		t.Fatalf("second update did not extend the change set: %+v", second)
+	}
+	if err := tools.ApplyChangeSet("talk.md", second.ID, []int{0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	viaProposal := mustRead(t, tools, "talk.md")
+	want := "---\ntheme: default\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Deux\n\n---\n\n# Trois\n"
+	if viaProposal != want {
+		t.Errorf("after applying:\n%q\nwant\n%q", viaProposal, want)
+	}
+
+	if err := tools.SaveSlides("talk.md", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.UpdatePage("talk.md", 1, "# Deux"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.UpdatePage("talk.md", 2, "# Trois"); err != nil {
+		t.Fatal(err)
+	}
+	if content := mustRead(t, tools, "talk.md"); content != viaProposal {
+		t.Errorf("UpdatePage and ProposePageUpdate disagree:\n%q\n%q", content, viaProposal)
+	}
+}
+
+func TestProposeInsertAndTheme(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	base := "---\ntheme: default\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Two\n"
+	if err := tools.SaveSlides("talk.md", base); err != nil {
+		t.Fatal(err)
+	}
+
+	// An inserted page is numbered in the proposed deck, so the next update
+	// of the same reply fills it in; nothing is written until accepted
+	cs, err := tools.ProposePageInsert("talk.md", 1, "two-cols", "Add a comparison")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.ProposePageUpdate("talk.md", 2, "# Three", "Add a comparison"); err != nil {
+		t.Fatal(err)
+	}
+	cs, err = tools.ProposeGlobalTheme("talk.md", "seriph", "Switch theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := mustRead(t, tools, "talk.md"); content != base {
+		t.Fatalf("deck written before review:\n%q", content)
+	}
+	if len(cs.Hunks) != 2 || cs.Summary != "Add a comparison; Switch theme" {
+		t.Fatalf("unexpected change set %+v", cs)
+	}
+	proposed, err := tools.PendingContent("talk.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"# One", "# Two", "# Three"}; !reflect.DeepEqual(Pages(proposed), want) {
+		t.Errorf("Pages(proposed) = %q, want %q", Pages(proposed), want)
+	}
+
+	if err := tools.ApplyChangeSet("talk.md", cs.ID, []int{0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	want := "---\ntheme: seriph\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Two\n\n---\nlayout: two-cols\n---\n\n# Three\n"
+	if content := mustRead(t, tools, "talk.md"); content != want {
+		t.Errorf("after applying:\n%q\nwant\n%q", content, want)
+	}
+}
@@ -0,0 +1,311 @@
+package slidev
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Deck is a Slidev markdown file split into slides. Parsing and String
+// round-trip: an unmodified deck serializes to its input. Slides hold LF
+// line endings; a deck read with CRLF is written back with CRLF.
+type Deck struct {
+	Slides          []Slide
+	trailingNewline bool
+	crlf            bool
+}
+
+// Slide is one slide of a deck. The first slide's frontmatter is the deck's
+// headmatter. Frontmatter and Content keep their raw text, each line ending
+// in a newline.
+type Slide struct {
+	Frontmatter    string // YAML between the --- lines, without them
+	HasFrontmatter bool
+	Content        string // Markdown after the frontmatter, including notes
+	Line           int    // 1-based line of the slide's first --- or content
+	ContentLine    int    // 1-based line where Content starts
+}
+
+// frontmatterLine matches the lines a slide frontmatter block consists of:
+// keys, indented continuations, list items and comments
+var frontmatterLine = regexp.MustCompile(`^(?:[A-Za-z_][\w.-]*\s*:(?:\s.*)?|\s+\S.*|-\s.*|#.*|)$`)
+
+// ParseDeck splits Slidev markdown into slides. A --- line inside a code
+// fence does not separate slides. A --- directly followed by YAML lines and
+// a closing --- starts a slide with frontmatter, as in Slidev.
+func ParseDeck(content string) *Deck {
+	d := &Deck{crlf: strings.Contains(content, "\r\n")}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	d.trailingNewline = strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	i := 0
+	var fence string
+	for {
+		slide := Slide{Line: i + 1}
+		if i < len(lines) && lines[i] == "---" {
+			if len(d.Slides) == 0 {
+				// Headmatter: everything up to the next ---, whatever it is
+				if end := indexOf(lines, "---", 1); end > 0 {
+					slide.Frontmatter = joinLines(lines[1:end])
+					slide.HasFrontmatter = true
+					i = end + 1
+				}
+			} else {
+				i++ // The separator
+				if end := frontmatterEnd(lines, i); end > 0 {
+					slide.Frontmatter = joinLines(lines[i:end])
+					slide.HasFrontmatter = true
+					i = end + 1
+				}
+			}
+		}
+		slide.ContentLine = i + 1
+
+		start := i
+		for ; i < len(lines); i++ {
+			var isFence bool
+			if fence, isFence = nextFence(fence, lines[i]); !isFence && fence == "" && lines[i] == "---" {
+				break
+			}
+		}
+		slide.Content = joinLines(lines[start:i])
+		d.Slides = append(d.Slides, slide)
+		if i >= len(lines) {
+			return d
+		}
+	}
+}
+
+// frontmatterKey matches a line setting a top-level frontmatter key
+var frontmatterKey = regexp.MustCompile(`^[A-Za-z_][\w.-]*\s*:(?:\s|$)`)
+
+// frontmatterEnd returns the index of the --- closing a frontmatter block
+// that starts at line i, or -1 when the lines there are not frontmatter. The
+// block must start with a key, so a heading right after a separator stays
+// slide content.
+func frontmatterEnd(lines []string, i int) int {
+	if i >= len(lines) || !frontmatterKey.MatchString(lines[i]) {
+		return -1
+	}
+	for j := i; j < len(lines); j++ {
+		if lines[j] == "---" {
+			return j
+		}
+		if !frontmatterLine.MatchString(lines[j]) {
+			return -1
+		}
+	}
+	return -1
+}
+
+// fenceMarker returns the ``` or ~~~ run starting a line, "" for other lines
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, c+c+c) {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+			return strings.Repeat(c, n)
+		}
+	}
+	return ""
+}
+
+// nextFence returns the code fence open after line, given the fence open
+// before it ("" for none), and whether line opens or closes a fence. A fence
+// is closed by a run of the same character at least as long as its opener.
+func nextFence(fence, line string) (string, bool) {
+	marker := fenceMarker(line)
+	switch {
+	case marker == "":
+		return fence, false
+	case fence == "":
+		return marker, true
+	case marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == marker:
+		return "", true
+	}
+	return fence, false
+}
+
+func indexOf(lines []string, s string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if lines[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// String serializes the deck back to Slidev markdown
+func (d *Deck) String() string {
+	var b strings.Builder
+	for i := range d.Slides {
+		b.WriteString(d.slideText(i))
+	}
+	out := b.String()
+	if !d.trailingNewline {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	if d.crlf {
+		out = strings.ReplaceAll(out, "\n", "\r\n")
+	}
+	return out
+}
+
+// slideText returns the markdown of slide i including the --- lines before
+// and after its frontmatter, as String writes it
+func (d *Deck) slideText(i int) string {
+	s := d.Slides[i]
+	var b strings.Builder
+	if i > 0 || s.HasFrontmatter {
+		b.WriteString("---\n")
+	}
+	if s.HasFrontmatter {
+		b.WriteString(s.Frontmatter)
+		b.WriteString("---\n")
+	}
+	b.WriteString(s.Content)
+	return b.String()
+}
+
+// Pages returns the content of each page of Slidev markdown, without
+// frontmatter, numbered as UpdatePage and InsertPage number them
+func Pages(content string) []string {
+	deck := ParseDeck(content)
+	pages := make([]string, len(deck.Slides))
+	for i, s := range deck.Slides {
+		pages[i] = strings.TrimSpace(s.Content)
+	}
+	return pages
+}
+
+// Headmatter returns the deck's headmatter, the first slide's frontmatter
+func (d *Deck) Headmatter() string {
+	if len(d.Slides) == 0 {
+		return ""
+	}
+	return d.Slides[0].Frontmatter
+}
+
+// notesPattern matches the HTML comment ending a slide, which Slidev shows as
+// presenter notes
+var notesPattern = regexp.MustCompile(`(?s)<!--((?:[^-]|-[^-]|--[^>])*)-->\s*$`)
+
+// Notes returns the slide's presenter notes, "" when it has none
+func (s Slide) Notes() string {
+	if _, notes, ok := s.splitNotes(); ok {
+		return notes
+	}
+	return ""
+}
+
+// Body returns the slide's markdown without its presenter notes
+func (s Slide) Body() string {
+	body, _, _ := s.splitNotes()
+	return body
+}
+
+func (s Slide) splitNotes() (body, notes string, ok bool) {
+	comment, start, end, ok := s.notesSpan()
+	if !ok {
+		return s.Content, "", false
+	}
+	return s.Content[:comment], strings.TrimSpace(s.Content[start:end]), true
+}
+
+// notesSpan returns where the notes comment starts in Content and where the
+// text inside it starts and ends, or ok false when the slide has no notes
+func (s Slide) notesSpan() (comment, start, end int, ok bool) {
+	loc := notesPattern.FindStringSubmatchIndex(s.Content)
+	if loc == nil {
+		return len(s.Content), 0, 0, false
+	}
+	return loc[0], loc[2], loc[3], true
+}
+
+// SetNotes replaces the slide's presenter notes with text, or removes them
+// when text is empty. A "-->" in text is escaped so it can't end the
+// comment early.
+func (s *Slide) SetNotes(text string) {
+	comment, _, _, _ := s.notesSpan()
+	body := strings.TrimRight(s.Content[:comment], " \t\n")
+	trailing := s.Content[len(strings.TrimRight(s.Content, " \t\n")):]
+	text = strings.TrimSpace(strings.ReplaceAll(text, "-->", "--&gt;"))
+	if text == "" {
+		s.Content = body + trailing
+		return
+	}
+	if body != "" {
+		body += "\n\n"
+	} else if s.HasFrontmatter || s.Line > 1 {
+		body = "\n"
+	}
+	if trailing == "" {
+		trailing = "\n"
+	}
+	s.Content = body + "<!--\n" + text + "\n-->" + trailing
+}
+
+// Field returns the value of a top-level key of the slide's frontmatter, with
+// surrounding quotes removed, or "" when it is not set
+func (s Slide) Field(key string) string {
+	return s.Fields()[key]
+}
+
+// Fields returns the top-level keys of the slide's frontmatter with their
+// values as written, quotes removed. Nested values are not parsed: a key
+// whose value spans several lines maps to "".
+func (s Slide) Fields() map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(s.Frontmatter, "\n") {
+		if !frontmatterKey.MatchString(line) {
+			continue
+		}
+		k, v, _ := strings.Cut(line, ":")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' && v[len(v)-1] == '"' || v[0] == '\'' && v[len(v)-1] == '\'') {
+			v = v[1 : len(v)-1]
+		}
+		if _, dup := fields[k]; !dup {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// Layout returns the slide's layout, "" for Slidev's default
+func (s Slide) Layout() string {
+	return s.Field("layout")
+}
+
+var headingPattern = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// Title returns the slide's title: the title field of its frontmatter or its
+// first heading
+func (s Slide) Title() string {
+	if title := s.Field("title"); title != "" {
+		return title
+	}
+	var fence string
+	for _, line := range strings.Split(s.Body(), "\n") {
+		var isFence bool
+		if fence, isFence = nextFence(fence, line); !isFence && fence == "" {
+			if m := headingPattern.FindStringSubmatch(line); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package slidev
+
+import (
+	"testing"
+)
+
+const sampleDeck = `---
+theme: seriph
+title: Quarterly Review
+---
+
+# Q3 Review
+
+Revenue is up
+
+---
+layout: two-cols
+---
+
+# Revenue
+
+` + "```yaml" + `
+---
+not: a separator
+---
+` + "```" + `
+
+::right::
+
+Chart here
+
+<!--
+Mention the EMEA numbers
+-->
+
+---
+
+## No frontmatter
+
+---
+# Heading right after the separator
+---
+
+Last slide
+`
+
+func TestParseDeck(t *testing.T) {
+	deck := ParseDeck(sampleDeck)
+	if got := deck.String(); got != sampleDeck {
+		t.Fatalf("round trip changed the deck:\n%s", got)
+	}
+	if len(deck.Slides) != 5 {
+		t.Fatalf("expected 5 slides, got %d: %+v", len(deck.Slides), deck.Slides)
+	}
+
+	tests := []struct {
+		title, layout, notes string
+		line, contentLine    int
+	}{
+		{title: "Quarterly Review", line: 1, contentLine: 5},
+		{title: "Revenue", layout: "two-cols", notes: "Mention the EMEA numbers", line: 10, contentLine: 13},
+		{title: "No frontmatter", line: 30, contentLine: 31},
+		{title: "Heading right after the separator", line: 34, contentLine: 35},
+		{line: 36, contentLine: 37},
+	}
+	for i, tt := range tests {
+		s := deck.Slides[i]
+		if s.Title() != tt.title || s.Layout() != tt.layout || s.Notes() != tt.notes || s.Line != tt.line || s.ContentLine != tt.contentLine {
+			t.Errorf("slide %d: title %q layout %q notes %q lines %d/%d, want %+v", i, s.Title(), s.Layout(), s.Notes(), s.Line, s.ContentLine, tt)
+		}
+	}
+	if deck.Headmatter() != "theme: seriph\ntitle: Quarterly Review\n" {
+		t.Errorf("unexpected headmatter %q", deck.Headmatter())
+	}
+	if body := deck.Slides[1].Body(); body == deck.Slides[1].Content {
+		t.Errorf("body still contains the notes:\n%s", body)
+	}
+}
+
+func TestParseDeckRoundTrip(t *testing.T) {
+	for _, content := range []string{
+		"",
+		"\n",
+		"# Just one slide",
+		"# One\n---\n# Two",
+		"---\ntheme: default\n---",
+		"---\nunclosed headmatter\n",
+		"a\n---\n",
+		"---\n---\n---\n",
+		"```\n---\n",
+		"---\r\ntheme: default\r\n---\r\n\r\n# One\r\n---\r\n# Two\r\n",
+	} {
+		if got := ParseDeck(content).String(); got != content {
+			t.Errorf("ParseDeck(%q).String() = %q", content, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package slidev
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one element of a diff: kept, deleted or inserted
+type diffOp struct {
+	Kind byte // ' ', '-' or '+'
+	Text string
+}
+
+// diffStrings returns the shortest edit turning a into b, using the longest
+// common subsequence. It is quadratic, which is fine for the lines of a slide
+// or the words of a paragraph.
+func diffStrings(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		}
+	}
+	return ops
+}
+
+// diffContext is the number of unchanged lines shown around changes
+const diffContext = 2
+
+// unifiedDiff returns a unified diff of two texts, without file headers. The
+// hunk headers count lines from oldFirst and newFirst, so diffs of a slide
+// can use the line numbers of the deck.
+func unifiedDiff(before, after string, oldFirst, newFirst int) string {
+	ops := diffStrings(splitLines(before), splitLines(after))
+
+	var b strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and the end of its hunk
+		for start < len(ops) && ops[start].Kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := max(0, start-diffContext)
+		end, unchanged := start, 0
+		for end < len(ops) && unchanged <= 2*diffContext {
+			if ops[end].Kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			end++
+		}
+		end -= max(0, unchanged-diffContext)
+
+		oldLine, newLine := oldFirst, newFirst
+		for _, op := range ops[:from] {
+			if op.Kind != '+' {
+				oldLine++
+			}
+			if op.Kind != '-' {
+				newLine++
+			}
+		}
+		var oldCount, newCount int
+		for _, op := range ops[from:end] {
+			if op.Kind != '+' {
+				oldCount++
+			}
+			if op.Kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[from:end] {
+			b.WriteByte(op.Kind)
+			b.WriteString(op.Text)
+			b.WriteByte('\n')
+		}
+		start = end
+	}
+	return b.String()
+}
+
+// splitLines splits text into lines without their terminators
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,74 @@
+package slidev
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExportFormats are the formats `slidev export` can produce
+var ExportFormats = []string{"pdf", "png", "pptx", "md"}
+
+// ExportOptions configures a deck export
+type ExportOptions struct {
+	Format     string // One of ExportFormats, "pdf" when empty
+	Output     string // Relative to the workspace; derived from the deck name when empty
+	WithClicks bool   // Export every click step as a separate page
+	Range      string // Slides to export, e.g. "1,4-6"; all when empty
+	Dark       bool   // Export with the dark color scheme
+}
+
+// Export renders the deck filename in dir with `slidev export` and returns
+// the path of the output. Slidev's output is written to logs, which may be nil.
+// Exports need a Chromium installed for Playwright.
+func Export(ctx context.Context, dir, filename string, opts ExportOptions, logs io.Writer) (string, error) {
+	if filename == "" {
+		filename = "slides.md"
+	}
+	if opts.Format == "" {
+		opts.Format = "pdf"
+	}
+	if !slices.Contains(ExportFormats, opts.Format) {
+		return "", fmt.Errorf("unsupported export format %q (want one of %s)", opts.Format, strings.Join(ExportFormats, ", "))
+	}
+	if _, err := ResolvePath(dir, filename); err != nil {
+		return "", err
+	}
+	if opts.Output == "" {
+		opts.Output = strings.TrimSuffix(filename, filepath.Ext(filename))
+		if opts.Format != "png" { // PNG exports are a directory of images
+			opts.Output += "." + opts.Format
+		}
+	}
+	output, err := ResolvePath(dir, opts.Output)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"export", filename, "--format", opts.Format, "--output", opts.Output}
+	if opts.WithClicks {
+		args = append(args, "--with-clicks")
+	}
+	if opts.Range != "" {
+		args = append(args, "--range", opts.Range)
+	}
+	if opts.Dark {
+		args = append(args, "--dark")
+	}
+
+	cmd, err := slidevCommand(ctx, logs, args...)
+	if err != nil {
+		return "", err
+	}
+	cmd.SysProcAttr = getSysProcAttr()
+	cmd.Dir = dir
+	cmd.Stdout = logs
+	cmd.Stderr = logs
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("slidev export failed: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,251 @@
+package slidev
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Parts of a slide FindReplace can search
+const (
+	ScopeBody        = "body"
+	ScopeNotes       = "notes"
+	ScopeFrontmatter = "frontmatter"
+)
+
+// FindOptions configures FindReplace
+type FindOptions struct {
+	Regex         bool     `json:"regex"` // The pattern is a regular expression and the replacement may use $1
+	CaseSensitive bool     `json:"caseSensitive"`
+	WholeWord     bool     `json:"wholeWord"`
+	Scopes        []string `json:"scopes"`      // Parts of slides to search; body and notes when empty
+	IncludeCode   bool     `json:"includeCode"` // Also replace in code blocks and inline code
+	DryRun        bool     `json:"dryRun"`      // Only report what would change
+}
+
+// FindReplaceResult reports the replacements made or, for a dry run, the
+// ones that would be
+type FindReplaceResult struct {
+	Replacements int           `json:"replacements"`
+	Slides       []SlideChange `json:"slides"`  // Changed slides only
+	Applied      bool          `json:"applied"` // The deck was written
+}
+
+// SlideChange describes the replacements in one slide
+type SlideChange struct {
+	Slide        int    `json:"slide"` // 0-based slide index
+	Replacements int    `json:"replacements"`
+	Diff         string `json:"diff"` // Unified diff with the deck's line numbers
+}
+
+// FindReplace replaces pattern with replacement in a deck. Code blocks and
+// inline code are left alone unless opts.IncludeCode is set. Unless it is a
+// dry run, the changes are written as one operation that Undo reverts.
+func (t *Tools) FindReplace(filename, pattern, replacement string, opts FindOptions) (*FindReplaceResult, error) {
+	re, err := compileFind(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	scopes := map[string]bool{}
+	for _, s := range opts.Scopes {
+		if s != ScopeBody && s != ScopeNotes && s != ScopeFrontmatter {
+			return nil, fmt.Errorf("unknown scope %q", s)
+		}
+		scopes[s] = true
+	}
+	if len(scopes) == 0 {
+		scopes[ScopeBody], scopes[ScopeNotes] = true, true
+	}
+	replace := func(text string, code bool) (string, int) {
+		if code && !opts.IncludeCode {
+			return text, 0
+		}
+		return replaceAll(re, text, replacement, opts.Regex)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, err := t.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	before := string(data)
+	deck := ParseDeck(before)
+
+	result := &FindReplaceResult{}
+	shift := 0 // Lines the slides so far grew by, for the new line numbers
+	for i := range deck.Slides {
+		s := &deck.Slides[i]
+		oldText := deck.slideText(i)
+		n := 0
+		if scopes[ScopeFrontmatter] && s.HasFrontmatter {
+			var c int
+			s.Frontmatter, c = replace(s.Frontmatter, false)
+			n += c
+		}
+		comment, start, end, hasNotes := s.notesSpan()
+		body, tail := s.Content[:comment], s.Content[comment:]
+		if scopes[ScopeBody] {
+			var c int
+			body, c = replaceOutsideCode(body, replace)
+			n += c
+		}
+		if scopes[ScopeNotes] && hasNotes {
+			notes, c := replace(s.Content[start:end], false)
+			tail = s.Content[comment:start] + notes + s.Content[end:]
+			n += c
+		}
+		s.Content = body + tail
+		if n == 0 {
+			continue
+		}
+		newText := deck.slideText(i)
+		result.Replacements += n
+		result.Slides = append(result.Slides, SlideChange{
+			Slide:        i,
+			Replacements: n,
+			Diff:         unifiedDiff(oldText, newText, s.Line, s.Line+shift),
+		})
+		shift += strings.Count(newText, "\n") - strings.Count(oldText, "\n")
+	}
+	if opts.DryRun || result.Replacements == 0 {
+		return result, nil
+	}
+
+	after := deck.String()
+	if err := t.recordUndo(filename, fmt.Sprintf("Replace %q with %q", pattern, replacement), before, after); err != nil {
+		return nil, fmt.Errorf("failed to record undo: %w", err)
+	}
+	if err := t.writeDeck(filename, path, after); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// compileFind turns a FindReplace pattern into a regular expression
+func compileFind(pattern string, opts FindOptions) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	expr := pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if opts.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !opts.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	if re.MatchString("") {
+		return nil, fmt.Errorf("pattern %q matches empty text", pattern)
+	}
+	return re, nil
+}
+
+// replaceAll replaces the matches of re in text and counts them. With expand
+// set, $1 and ${name} in the replacement refer to submatches.
+func replaceAll(re *regexp.Regexp, text, replacement string, expand bool) (string, int) {
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		out = append(out, text[last:m[0]]...)
+		if expand {
+			out = re.ExpandString(out, replacement, text, m)
+		} else {
+			out = append(out, replacement...)
+		}
+		last = m[1]
+	}
+	out = append(out, text[last:]...)
+	return string(out), len(matches)
+}
+
+// replaceOutsideCode splits markdown into code, meaning fenced blocks and
+// inline code spans, and other text, and passes each part to replace
+func replaceOutsideCode(markdown string, replace func(text string, code bool) (string, int)) (string, int) {
+	var b strings.Builder
+	total := 0
+	emit := func(text string, code bool) {
+		if text == "" {
+			return
+		}
+		out, n := replace(text, code)
+		b.WriteString(out)
+		total += n
+	}
+
+	var fence string
+	var prose strings.Builder
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		var isFence bool
+		wasOpen := fence != ""
+		if fence, isFence = nextFence(fence, strings.TrimSuffix(line, "\n")); isFence || wasOpen {
+			for _, part := range splitInlineCode(prose.String()) {
+				emit(part.text, part.code)
+			}
+			prose.Reset()
+			emit(line, true)
+			continue
+		}
+		prose.WriteString(line)
+	}
+	for _, part := range splitInlineCode(prose.String()) {
+		emit(part.text, part.code)
+	}
+	return b.String(), total
+}
+
+type codeSpan struct {
+	text string
+	code bool
+}
+
+// splitInlineCode splits text at inline code spans: a run of backticks up to
+// the next run of the same length, as in CommonMark
+func splitInlineCode(text string) []codeSpan {
+	var parts []codeSpan
+	for {
+		open := strings.IndexByte(text, '`')
+		if open < 0 {
+			break
+		}
+		n := len(text[open:]) - len(strings.TrimLeft(text[open:], "`"))
+		closeAt := -1
+		for i := open + n; i < len(text); {
+			j := strings.IndexByte(text[i:], '`')
+			if j < 0 {
+				break
+			}
+			run := len(text[i+j:]) - len(strings.TrimLeft(text[i+j:], "`"))
+			if run == n {
+				closeAt = i + j
+				break
+			}
+			i += j + run
+		}
+		if closeAt < 0 {
+			// An unmatched run is literal text
+			parts = append(parts, codeSpan{text: text[:open+n]})
+			text = text[open+n:]
+			continue
+		}
+		parts = append(parts, codeSpan{text: text[:open]}, codeSpan{text: text[open : closeAt+n], code: true})
+		text = text[closeAt+n:]
+	}
+	return append(parts, codeSpan{text: text})
+}
@@ -0,0 +1,124 @@
+package slidev
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const acmeDeck = `---
+title: Acme Roadmap
+---
+
+# Acme in 2025
+
+Acme grows, ACME ships. Run ` + "`acme deploy`" + `.
+
+---
+layout: acme-cover
+---
+
+` + "```sh" + `
+acme --version
+` + "```" + `
+
+Acmeville is not affected
+
+<!--
+Thank the Acme team
+-->
+`
+
+func TestFindReplace(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	if err := tools.SaveSlides("acme.md", acmeDeck); err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run reports per-slide diffs and leaves the deck alone
+	res, err := tools.FindReplace("acme.md", "acme", "Initech", FindOptions{WholeWord: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Applied || res.Replacements != 4 || len(res.Slides) != 2 || res.Slides[0].Replacements != 3 || res.Slides[1].Replacements != 1 {
+		t.Fatalf("unexpected dry run result %+v", res)
+	}
+	if !strings.HasPrefix(res.Slides[0].Diff, "@@ -3,6 +3,6 @@\n ---\n \n-# Acme in 2025\n+# Initech in 2025\n") {
+		t.Errorf("unexpected diff:\n%s", res.Slides[0].Diff)
+	}
+	if content, _ := tools.ReadSlides("acme.md"); content != acmeDeck {
+		t.Fatalf("dry run changed the deck:\n%s", content)
+	}
+
+	res, err = tools.FindReplace("acme.md", "acme", "Initech", FindOptions{WholeWord: true})
+	if err != nil || !res.Applied {
+		t.Fatalf("FindReplace = %+v, %v", res, err)
+	}
+	content, _ := tools.ReadSlides("acme.md")
+	for _, want := range []string{"title: Acme Roadmap", "# Initech in 2025", "Initech grows, Initech ships", "`acme deploy`", "layout: acme-cover", "acme --version", "Acmeville", "Thank the Initech team"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("deck is missing %q:\n%s", want, content)
+		}
+	}
+
+	// Frontmatter only, with a regular expression
+	res, err = tools.FindReplace("acme.md", `(\w+) Roadmap`, "$1 Plan", FindOptions{Regex: true, Scopes: []string{ScopeFrontmatter}})
+	if err != nil || res.Replacements != 1 {
+		t.Fatalf("FindReplace in frontmatter = %+v, %v", res, err)
+	}
+	if content, _ := tools.ReadSlides("acme.md"); !strings.Contains(content, "title: Acme Plan") {
+		t.Errorf("frontmatter not replaced:\n%s", content)
+	}
+
+	if history, _ := tools.UndoHistory("acme.md"); len(history) != 2 || !strings.Contains(history[0].Label, "Roadmap") {
+		t.Errorf("unexpected history %+v", history)
+	}
+	for range 2 {
+		if _, err := tools.Undo("acme.md"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if content, _ := tools.ReadSlides("acme.md"); content != acmeDeck {
+		t.Errorf("undo did not restore the deck:\n%s", content)
+	}
+	if _, err := tools.Undo("acme.md"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	// Edits after a replacement block its undo
+	if _, err := tools.FindReplace("acme.md", "Acmeville", "Springfield", FindOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.UpdatePage("acme.md", 0, "# Edited"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.Undo("acme.md"); !errors.Is(err, ErrUndoConflict) {
+		t.Errorf("expected ErrUndoConflict, got %v", err)
+	}
+
+	if err := tools.SaveSlides("windows.md", strings.ReplaceAll(acmeDeck, "\n", "\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.FindReplace("windows.md", "Acmeville", "Springfield", FindOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if content := mustRead(t, tools, "windows.md"); strings.Count(content, "\n") != strings.Count(content, "\r\n") || !strings.Contains(content, "Springfield") {
+		t.Errorf("CRLF deck after FindReplace:\n%q", content)
+	}
+
+	for _, pattern := range []string{"", "x*", "("} {
+		if _, err := tools.FindReplace("acme.md", pattern, "y", FindOptions{Regex: true}); err == nil {
+			t.Errorf("pattern %q accepted", pattern)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nJ\nk\n"
+	want := "@@ -10,4 +10,4 @@\n a\n-b\n+B\n c\n d\n" +
+		"@@ -17,3 +17,4 @@\n h\n i\n-j\n+J\n+k\n"
+	if got := unifiedDiff(before, after, 10, 10); got != want {
+		t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package slidev
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FormatMarkdown returns Slidev markdown in canonical form:
+//   - LF line endings, no trailing whitespace except hard line breaks, and
+//     a single newline at the end
+//   - one blank line after each --- and frontmatter block and one before
+//     each ---, and no runs of blank lines within slides
+//   - no blank lines at the start or end of frontmatter
+//   - - as the marker of unordered lists
+//
+// Code blocks and <style> and <script> blocks are left as they are.
+// Formatting formatted markdown changes nothing.
+func FormatMarkdown(content string) string {
+	deck := ParseDeck(content)
+	deck.trailingNewline = true
+	deck.crlf = false
+	last := len(deck.Slides) - 1
+	for i := range deck.Slides {
+		s := &deck.Slides[i]
+		if s.HasFrontmatter {
+			s.Frontmatter = formatFrontmatter(s.Frontmatter)
+		}
+		body := formatContent(s.Content)
+		lead := "\n"
+		if i == 0 && !s.HasFrontmatter {
+			lead = ""
+		}
+		switch {
+		case body == "" && i == last:
+			s.Content = ""
+		case body == "":
+			// Keeps a blank line before the next ---, and a deck from
+			// starting with a --- that would read as headmatter
+			s.Content = "\n"
+		case i == last:
+			s.Content = lead + body + "\n"
+		default:
+			s.Content = lead + body + "\n\n"
+		}
+	}
+	return deck.String()
+}
+
+// formatFrontmatter trims trailing whitespace and surrounding blank lines
+// of a frontmatter block
+func formatFrontmatter(frontmatter string) string {
+	lines := splitLines(frontmatter)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return joinLines(trimBlankLines(lines))
+}
+
+var (
+	bulletMarker  = regexp.MustCompile(`^(\s*)[*+](\s+\S)`)
+	thematicBreak = regexp.MustCompile(`^\s*(?:\*\s*){3,}$|^\s*(?:\+\s*){3,}$`)
+	rawBlockStart = regexp.MustCompile(`(?i)^\s*<(style|script)\b`)
+)
+
+// formatContent formats the markdown of a slide and returns it without
+// leading and trailing blank lines or a final newline
+func formatContent(content string) string {
+	lines := splitLines(content)
+	out := make([]string, 0, len(lines))
+	var fence, raw string
+	for i, line := range lines {
+		wasOpen := fence != ""
+		var isFence bool
+		if raw == "" {
+			fence, isFence = nextFence(fence, line)
+		}
+		switch {
+		case wasOpen:
+			out = append(out, line) // Code, including its closing fence
+			continue
+		case raw != "":
+			out = append(out, line)
+			if strings.Contains(strings.ToLower(line), "</"+raw) {
+				raw = ""
+			}
+			continue
+		case isFence:
+			out = append(out, strings.TrimRight(line, " \t"))
+			continue
+		}
+		if m := rawBlockStart.FindStringSubmatch(line); m != nil && !strings.Contains(strings.ToLower(line), "</"+strings.ToLower(m[1])) {
+			raw = strings.ToLower(m[1])
+			out = append(out, strings.TrimRight(line, " \t"))
+			continue
+		}
+
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed == "" {
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+		// Two trailing spaces before more text are a hard line break
+		if strings.HasSuffix(line, "  ") && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			trimmed += "  "
+		}
+		if !thematicBreak.MatchString(trimmed) {
+			trimmed = bulletMarker.ReplaceAllString(trimmed, "${1}-${2}")
+		}
+		out = append(out, trimmed)
+	}
+	if fence == "" && raw == "" {
+		out = trimBlankLines(out)
+	}
+	return strings.Join(out, "\n")
+}
+
+// trimBlankLines drops blank lines at the start and end of lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// FormatDeck rewrites a deck with FormatMarkdown as one undoable operation
+// and reports whether anything changed
+func (t *Tools) FormatDeck(filename string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, err := t.resolve(filename)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	before := string(data)
+	after := FormatMarkdown(before)
+	if after == before {
+		return false, nil
+	}
+	if err := t.recordUndo(filename, "Format deck", before, after); err != nil {
+		return false, fmt.Errorf("failed to record undo: %w", err)
+	}
+	return true, t.writeDeck(filename, path, after)
+}
+
+// SetFormatOnSave turns formatting decks with FormatMarkdown in SaveSlides
+// on or off
+func (t *Tools) SetFormatOnSave(on bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.formatOnSave = on
+}
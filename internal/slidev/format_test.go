@@ -0,0 +1,80 @@
+package slidev
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of TestFormatMarkdown")
+
+// TestFormatMarkdown formats each testdata/format/*.md file and compares the
+// result with the .golden file next to it
+func TestFormatMarkdown(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "format", "*.md"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no test inputs: %v", err)
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := FormatMarkdown(string(data))
+			golden := strings.TrimSuffix(input, ".md") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("formatted %s differs from %s:\n%s", input, golden, got)
+			}
+			if again := FormatMarkdown(got); again != got {
+				t.Errorf("formatting is not idempotent:\n%s", again)
+			}
+			if len(ParseDeck(got).Slides) != len(ParseDeck(string(data)).Slides) {
+				t.Errorf("formatting changed the number of slides")
+			}
+		})
+	}
+}
+
+func TestFormatOnSave(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	messy := "# Title   \n\n\n* item\n"
+	if err := tools.SaveSlides("deck.md", messy); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("deck.md"); content != messy {
+		t.Errorf("saved without format on save: %q", content)
+	}
+	if changed, err := tools.FormatDeck("deck.md"); err != nil || !changed {
+		t.Fatalf("FormatDeck = %v, %v", changed, err)
+	}
+	if changed, _ := tools.FormatDeck("deck.md"); changed {
+		t.Error("formatting a formatted deck changed it")
+	}
+	if _, err := tools.Undo("deck.md"); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("deck.md"); content != messy {
+		t.Errorf("undo did not restore the deck: %q", content)
+	}
+
+	tools.SetFormatOnSave(true)
+	if err := tools.SaveSlides("deck.md", messy); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := tools.ReadSlides("deck.md"); content != "# Title\n\n- item\n" {
+		t.Errorf("not formatted on save: %q", content)
+	}
+}
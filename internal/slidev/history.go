@@ -0,0 +1,157 @@
+package slidev
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNothingToUndo is returned by Undo for decks without undoable operations
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrUndoConflict is returned by Undo when the deck was changed after the
+// operation to undo, so restoring it would lose those changes
+var ErrUndoConflict = errors.New("deck changed since the operation")
+
+// UndoEntry describes an operation on a deck that can be undone
+type UndoEntry struct {
+	ID    string    `json:"id"`
+	Label string    `json:"label"` // What the operation did, e.g. `Replace "Acme" with "Initech"`
+	Time  time.Time `json:"time"`
+}
+
+// undoRecord is an UndoEntry with the deck's content before and after it
+type undoRecord struct {
+	UndoEntry
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// maxUndoEntries bounds the undo history kept per deck
+const maxUndoEntries = 20
+
+// undoDir holds a deck's undo history, one JSON file per operation named so
+// that they sort by time. The caller must hold t.mu.
+func (t *Tools) undoDir(filename string) string {
+	return filepath.Join(t.deckDataDir(filename), "undo")
+}
+
+// recordUndo saves the content of a deck before and after an operation, so
+// Undo can restore it, and drops the oldest entries beyond maxUndoEntries.
+// The caller must hold t.mu.
+func (t *Tools) recordUndo(filename, label, before, after string) error {
+	dir := t.undoDir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	now := time.Now()
+	rec := undoRecord{UndoEntry: UndoEntry{ID: fmt.Sprintf("%020d", now.UnixNano()), Label: label, Time: now}, Before: before, After: after}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, rec.ID+".json"), data, 0644); err != nil {
+		return err
+	}
+	ids, err := undoIDs(dir)
+	if err != nil {
+		return err
+	}
+	for len(ids) > maxUndoEntries {
+		os.Remove(filepath.Join(dir, ids[0]+".json"))
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// undoIDs lists the undo entries in dir, oldest first
+func undoIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// UndoHistory lists the operations on a deck that can be undone, latest first
+func (t *Tools) UndoHistory(filename string) ([]UndoEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.resolve(filename); err != nil {
+		return nil, err
+	}
+	dir := t.undoDir(filename)
+	ids, err := undoIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]UndoEntry, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		rec, err := readUndoRecord(dir, ids[i])
+		if err != nil {
+			continue // Skip entries that can't be read rather than hide the rest
+		}
+		history = append(history, rec.UndoEntry)
+	}
+	return history, nil
+}
+
+func readUndoRecord(dir, id string) (undoRecord, error) {
+	var rec undoRecord
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return rec, err
+	}
+	err = json.Unmarshal(data, &rec)
+	return rec, err
+}
+
+// Undo reverts the latest undoable operation on a deck and returns it. It
+// fails with ErrUndoConflict when the deck was edited since.
+func (t *Tools) Undo(filename string) (UndoEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, err := t.resolve(filename)
+	if err != nil {
+		return UndoEntry{}, err
+	}
+	dir := t.undoDir(filename)
+	ids, err := undoIDs(dir)
+	if err != nil {
+		return UndoEntry{}, err
+	}
+	if len(ids) == 0 {
+		return UndoEntry{}, fmt.Errorf("%w in %s", ErrNothingToUndo, filename)
+	}
+	id := ids[len(ids)-1]
+	rec, err := readUndoRecord(dir, id)
+	if err != nil {
+		return UndoEntry{}, fmt.Errorf("failed to read undo entry %s: %w", id, err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return UndoEntry{}, err
+	}
+	if string(current) != rec.After {
+		return UndoEntry{}, fmt.Errorf("%w: %s", ErrUndoConflict, rec.Label)
+	}
+	if err := t.writeDeck(filename, path, rec.Before); err != nil {
+		return UndoEntry{}, err
+	}
+	if err := os.Remove(filepath.Join(dir, id+".json")); err != nil {
+		return UndoEntry{}, err
+	}
+	return rec.UndoEntry, nil
+}
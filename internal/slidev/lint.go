@@ -0,0 +1,445 @@
+package slidev
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity ranks a lint diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Slidev renders the deck wrong or not at all
+	SeverityWarning Severity = "warning" // Likely a mistake
+	SeverityInfo    Severity = "info"    // A content suggestion
+)
+
+// Diagnostic is a problem a lint rule found in a deck
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Slide    int      `json:"slide"`  // 0-based slide index
+	Line     int      `json:"line"`   // 1-based line in the deck
+	Column   int      `json:"column"` // 1-based, in characters
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (slide %d, %s)", d.Line, d.Column, d.Severity, d.Message, d.Slide+1, d.Rule)
+}
+
+// Rule is a lint check. Check reports diagnostics without setting Rule,
+// which the linter fills in with Name.
+type Rule struct {
+	Name  string
+	Check func(doc *LintDocument) []Diagnostic
+}
+
+// LintOptions configures a Linter
+type LintOptions struct {
+	// Dir is the directory of the deck, used to find images and custom
+	// layouts. Those checks are skipped when it is empty.
+	Dir string
+	// Layouts are layouts besides Slidev's built-in ones, e.g. a theme's
+	Layouts []string
+	// MaxWords and MaxBullets bound the text of a slide; 0 means the defaults
+	MaxWords   int
+	MaxBullets int
+}
+
+// Defaults for LintOptions
+const (
+	DefaultMaxWords   = 120
+	DefaultMaxBullets = 8
+)
+
+// BuiltinLayouts are the layouts Slidev ships
+var BuiltinLayouts = []string{
+	"center", "cover", "default", "end", "fact", "full", "iframe", "iframe-left",
+	"iframe-right", "image", "image-left", "image-right", "intro", "none", "quote",
+	"section", "statement", "two-cols", "two-cols-header",
+}
+
+// LintDocument is the deck a rule checks
+type LintDocument struct {
+	Source  string // Markdown as read, with LF line endings
+	Deck    *Deck
+	Options LintOptions
+}
+
+// Linter checks decks against a set of rules
+type Linter struct {
+	Rules   []Rule
+	Options LintOptions
+}
+
+// NewLinter returns a linter with DefaultRules
+func NewLinter(opts LintOptions) *Linter {
+	return &Linter{Rules: DefaultRules(), Options: opts}
+}
+
+// DefaultRules returns the built-in rules: frontmatter and code fence
+// syntax, layout names, layout slots, text density, image references and
+// headings
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "frontmatter", Check: checkFrontmatter},
+		{Name: "code-fence", Check: checkCodeFences},
+		{Name: "layout", Check: checkLayouts},
+		{Name: "slots", Check: checkSlots},
+		{Name: "density", Check: checkDensity},
+		{Name: "image", Check: checkImages},
+		{Name: "heading", Check: checkHeadings},
+	}
+}
+
+// Lint checks Slidev markdown and returns the diagnostics in deck order
+func (l *Linter) Lint(content string) []Diagnostic {
+	opts := l.Options
+	if opts.MaxWords <= 0 {
+		opts.MaxWords = DefaultMaxWords
+	}
+	if opts.MaxBullets <= 0 {
+		opts.MaxBullets = DefaultMaxBullets
+	}
+	source := strings.ReplaceAll(content, "\r\n", "\n")
+	doc := &LintDocument{Source: source, Deck: ParseDeck(source), Options: opts}
+
+	var diags []Diagnostic
+	for _, rule := range l.Rules {
+		for _, d := range rule.Check(doc) {
+			d.Rule = rule.Name
+			diags = append(diags, d)
+		}
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+	return diags
+}
+
+// LintDeck lints a deck of the workspace with the default rules
+func (t *Tools) LintDeck(filename string, opts LintOptions) ([]Diagnostic, error) {
+	t.mu.Lock()
+	path, err := t.resolve(filename)
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(path)
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Dir == "" {
+		opts.Dir = filepath.Dir(path)
+	}
+	return NewLinter(opts).Lint(string(data)), nil
+}
+
+// HasErrors reports whether any diagnostic is an error
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// contentLines returns the lines of a slide's content and the deck line of
+// the first
+func contentLines(s Slide) ([]string, int) {
+	return splitLines(s.Content), s.ContentLine
+}
+
+// codeLines marks the lines of a slide's content inside code fences,
+// including the fence lines. Fences are tracked per slide.
+func codeLines(lines []string) []bool {
+	code := make([]bool, len(lines))
+	var fence string
+	for i, line := range lines {
+		wasOpen := fence != ""
+		var isFence bool
+		fence, isFence = nextFence(fence, line)
+		code[i] = isFence || wasOpen
+	}
+	return code
+}
+
+func diag(severity Severity, slide, line, column int, format string, args ...any) Diagnostic {
+	return Diagnostic{Severity: severity, Slide: slide, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// column converts a byte offset in line into a 1-based character column
+func column(line string, offset int) int {
+	return utf8.RuneCountInString(line[:offset]) + 1
+}
+
+// checkFrontmatter reports headmatter without its closing ---, frontmatter
+// lines that aren't YAML keys, and slides starting with keys that are not
+// inside a frontmatter block
+func checkFrontmatter(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	slides := doc.Deck.Slides
+	if strings.HasPrefix(doc.Source, "---\n") && !slides[0].HasFrontmatter {
+		diags = append(diags, diag(SeverityError, 0, 1, 1, "headmatter is not closed with ---"))
+	}
+	for i, s := range slides {
+		for j, line := range splitLines(s.Frontmatter) {
+			if !frontmatterLine.MatchString(line) {
+				diags = append(diags, diag(SeverityError, i, s.Line+1+j, 1, "invalid frontmatter line %q, expected key: value", line))
+			}
+		}
+		lines, first := contentLines(s)
+		if !s.HasFrontmatter && i > 0 && len(lines) > 0 && frontmatterKey.MatchString(lines[0]) {
+			diags = append(diags, diag(SeverityWarning, i, first, 1, "frontmatter %q is missing its closing ---", lines[0]))
+		}
+	}
+	return diags
+}
+
+// checkCodeFences reports code fences left open at the end of a slide. An
+// open fence swallows the following --- separators, merging slides.
+func checkCodeFences(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	for i, s := range doc.Deck.Slides {
+		lines, first := contentLines(s)
+		var fence string
+		open := 0
+		for j, line := range lines {
+			var isFence bool
+			before := fence
+			if fence, isFence = nextFence(fence, line); isFence && before == "" {
+				open = j
+			}
+		}
+		if fence != "" {
+			indent := len(lines[open]) - len(strings.TrimLeft(lines[open], " "))
+			diags = append(diags, diag(SeverityError, i, first+open, indent+1, "code block opened with %s is never closed", fence))
+		}
+	}
+	return diags
+}
+
+// knownLayouts returns the layouts the deck may use, including custom ones
+// in the layouts folder next to it
+func (doc *LintDocument) knownLayouts() map[string]bool {
+	known := map[string]bool{}
+	for _, l := range BuiltinLayouts {
+		known[l] = true
+	}
+	for _, l := range doc.Options.Layouts {
+		known[l] = true
+	}
+	if doc.Options.Dir != "" {
+		entries, _ := os.ReadDir(filepath.Join(doc.Options.Dir, "layouts"))
+		for _, e := range entries {
+			if name, ok := strings.CutSuffix(e.Name(), ".vue"); ok {
+				known[name] = true
+			}
+		}
+	}
+	return known
+}
+
+// checkLayouts reports layouts that are neither built in, the theme's nor
+// custom
+func checkLayouts(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	known := doc.knownLayouts()
+	for i, s := range doc.Deck.Slides {
+		layout := s.Layout()
+		if layout == "" || known[layout] {
+			continue
+		}
+		line, col := s.fieldPosition("layout")
+		diags = append(diags, diag(SeverityError, i, line, col, "unknown layout %q", layout))
+	}
+	return diags
+}
+
+// fieldPosition returns the deck line and column of a frontmatter key's value
+func (s Slide) fieldPosition(key string) (int, int) {
+	for j, line := range splitLines(s.Frontmatter) {
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(k) == key && !strings.HasPrefix(line, " ") {
+			return s.Line + 1 + j, column(line, len(line)-len(strings.TrimLeft(v, " ")))
+		}
+	}
+	return s.Line, 1
+}
+
+var slotPattern = regexp.MustCompile(`^::([\w-]+)::\s*$`)
+
+// slotLayouts lists the named slots of the built-in layouts that have them
+var slotLayouts = map[string][]string{
+	"two-cols":        {"left", "right"},
+	"two-cols-header": {"left", "right"},
+}
+
+// checkSlots reports ::name:: slot markers the slide's built-in layout
+// doesn't have, such as ::right:: without two-cols. Custom layouts may
+// define any slots.
+func checkSlots(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	builtin := map[string]bool{}
+	for _, l := range BuiltinLayouts {
+		builtin[l] = true
+	}
+	for i, s := range doc.Deck.Slides {
+		layout := s.Layout()
+		if layout == "" {
+			layout = "default"
+		}
+		if !builtin[layout] {
+			continue
+		}
+		lines, first := contentLines(s)
+		code := codeLines(lines)
+		for j, line := range lines {
+			m := slotPattern.FindStringSubmatch(line)
+			if m == nil || code[j] || m[1] == "default" {
+				continue
+			}
+			if slices.Contains(slotLayouts[layout], m[1]) {
+				continue
+			}
+			if m[1] == "left" || m[1] == "right" {
+				diags = append(diags, diag(SeverityWarning, i, first+j, 1, "slot ::%s:: needs layout: two-cols, the slide uses %s", m[1], layout))
+			} else {
+				diags = append(diags, diag(SeverityWarning, i, first+j, 1, "layout %s has no slot named %s", layout, m[1]))
+			}
+		}
+	}
+	return diags
+}
+
+var bulletPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\S`)
+
+// checkDensity reports slides with more words or bullets than an audience
+// can take in
+func checkDensity(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	for i, s := range doc.Deck.Slides {
+		lines := splitLines(s.Body())
+		code := codeLines(lines)
+		words, bullets := 0, 0
+		for j, line := range lines {
+			if code[j] || strings.HasPrefix(strings.TrimSpace(line), "<") {
+				continue // Code and HTML tags aren't read out
+			}
+			words += len(tokenizeWords(line))
+			if bulletPattern.MatchString(line) {
+				bullets++
+			}
+		}
+		if words > doc.Options.MaxWords {
+			diags = append(diags, diag(SeverityWarning, i, s.Line, 1, "slide has %d words, more than %d; split it or move text to the notes", words, doc.Options.MaxWords))
+		}
+		if bullets > doc.Options.MaxBullets {
+			diags = append(diags, diag(SeverityWarning, i, s.Line, 1, "slide has %d bullet points, more than %d", bullets, doc.Options.MaxBullets))
+		}
+	}
+	return diags
+}
+
+// tokenizeWords splits a line of markdown into words, ignoring markup
+func tokenizeWords(line string) []string {
+	var words []string
+	for _, f := range strings.Fields(line) {
+		if strings.Trim(f, "#*_-+>|`[]()!:.,;") != "" {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+var imagePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`),
+	regexp.MustCompile(`<img\b[^>]*\bsrc\s*=\s*["']([^"']+)["']`),
+}
+
+// imageFields are frontmatter keys of built-in layouts naming an image
+var imageFields = []string{"image", "background"}
+
+// checkImages reports local images that don't exist. Paths starting with /
+// are looked up in the public folder, as Slidev serves them from there.
+func checkImages(doc *LintDocument) []Diagnostic {
+	if doc.Options.Dir == "" {
+		return nil
+	}
+	var diags []Diagnostic
+	for i, s := range doc.Deck.Slides {
+		for _, key := range imageFields {
+			if src := s.Field(key); src != "" && !doc.imageExists(src) {
+				line, col := s.fieldPosition(key)
+				diags = append(diags, diag(SeverityWarning, i, line, col, "image %s not found", src))
+			}
+		}
+		lines, first := contentLines(s)
+		code := codeLines(lines)
+		for j, line := range lines {
+			if code[j] {
+				continue
+			}
+			for _, re := range imagePatterns {
+				for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+					if src := line[m[2]:m[3]]; !doc.imageExists(src) {
+						diags = append(diags, diag(SeverityWarning, i, first+j, column(line, m[2]), "image %s not found", src))
+					}
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// imageExists reports whether an image reference resolves to a file. Remote
+// images and references it can't resolve count as existing.
+func (doc *LintDocument) imageExists(src string) bool {
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.Contains(u.Path, "{{") {
+		return true
+	}
+	p, err := url.PathUnescape(u.Path)
+	if err != nil {
+		return true
+	}
+	var file string
+	if strings.HasPrefix(p, "/") {
+		file = filepath.Join(doc.Options.Dir, "public", filepath.FromSlash(path.Clean(p)))
+	} else {
+		file = filepath.Join(doc.Options.Dir, filepath.FromSlash(p))
+	}
+	_, err = os.Stat(file)
+	return err == nil
+}
+
+// headinglessLayouts are layouts whose slides usually show no heading
+var headinglessLayouts = map[string]bool{"end": true, "full": true, "iframe": true, "image": true, "none": true, "fact": true, "quote": true, "statement": true}
+
+// checkHeadings reports slides without a heading or title, which leave the
+// audience and the slide overview without orientation
+func checkHeadings(doc *LintDocument) []Diagnostic {
+	var diags []Diagnostic
+	for i, s := range doc.Deck.Slides {
+		if headinglessLayouts[s.Layout()] || strings.TrimSpace(s.Body()) == "" || s.Title() != "" {
+			continue
+		}
+		if strings.Contains(s.Body(), "<h1") || strings.Contains(s.Body(), "<h2") {
+			continue
+		}
+		diags = append(diags, diag(SeverityInfo, i, s.Line, 1, "slide has no heading"))
+	}
+	return diags
+}
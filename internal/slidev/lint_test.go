@@ -0,0 +1,104 @@
+package slidev
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"public/logo.png", "img/chart.png", "layouts/brand.vue"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deck := `---
+theme: default
+this is not yaml
+---
+
+# Cover
+
+![logo](/logo.png) ![chart](./img/chart.png) ![gone](./img/gone.png) ![remote](https://example.com/x.png)
+
+---
+layout: two-colls
+---
+
+# Columns
+
+::right::
+
+Right
+
+---
+layout: brand
+---
+
+# Custom
+
+::aside::
+
+---
+
+# Wrong slot
+
+::right::
+
+---
+layout: image-right
+image: ./img/missing.jpg
+---
+
+Just text, no heading
+
+---
+layout: center
+Missing the closing separator
+
+---
+
+# Dense
+
+- a
+- b
+- c
+
+` + "```js" + `
+const open = true
+`
+	diags := NewLinter(LintOptions{Dir: dir, MaxBullets: 2}).Lint(deck)
+
+	want := []string{
+		"3:1 error frontmatter",
+		"8:54 warning image",
+		"11:9 error layout",
+		"32:1 warning slots",
+		"34:1 info heading",
+		"36:8 warning image",
+		"41:1 info heading",
+		"42:1 warning frontmatter",
+		"45:1 warning density",
+		"53:1 error code-fence",
+	}
+	var got []string
+	for _, d := range diags {
+		got = append(got, fmt.Sprintf("%d:%d %s %s", d.Line, d.Column, d.Severity, d.Rule))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got diagnostics\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !HasErrors(diags) {
+		t.Error("HasErrors = false")
+	}
+	if diags := NewLinter(LintOptions{}).Lint("---\ntheme: default\n---\n\n# Fine\n\n---\nlayout: two-cols\n---\n\n# Columns\n\n::right::\n\nText\n"); len(diags) != 0 {
+		t.Errorf("clean deck has diagnostics: %v", diags)
+	}
+}
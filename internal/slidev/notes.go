@@ -0,0 +1,50 @@
+package slidev
+
+import (
+	"fmt"
+	"os"
+)
+
+// GetNotes returns the presenter notes of a slide, "" when it has none
+func (t *Tools) GetNotes(filename string, slide int) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, deck, err := t.readDeck(filename)
+	if err != nil {
+		return "", err
+	}
+	if slide < 0 || slide >= len(deck.Slides) {
+		return "", fmt.Errorf("%w: %d", ErrPageOutOfRange, slide)
+	}
+	return deck.Slides[slide].Notes(), nil
+}
+
+// SetNotes replaces the presenter notes of a slide, leaving its content as
+// it is. Empty text removes the notes.
+func (t *Tools) SetNotes(filename string, slide int, text string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, deck, err := t.readDeck(filename)
+	if err != nil {
+		return err
+	}
+	if slide < 0 || slide >= len(deck.Slides) {
+		return fmt.Errorf("%w: %d", ErrPageOutOfRange, slide)
+	}
+	deck.Slides[slide].SetNotes(text)
+	return t.writeDeck(filename, path, deck.String())
+}
+
+// readDeck reads and parses a deck, returning its path. The caller must hold
+// t.mu.
+func (t *Tools) readDeck(filename string) (string, *Deck, error) {
+	path, err := t.resolve(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, ParseDeck(string(data)), nil
+}
@@ -0,0 +1,55 @@
+package slidev
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNotes(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	deck := "---\ntheme: default\n---\n\n# Cover\n\n---\n\n# Agenda\n\n- One\n\n<!-- Old notes -->\n\n---\nlayout: end\n---\n"
+	if err := tools.SaveSlides("talk.md", deck); err != nil {
+		t.Fatal(err)
+	}
+
+	if notes, err := tools.GetNotes("talk.md", 1); err != nil || notes != "Old notes" {
+		t.Errorf("GetNotes = %q, %v", notes, err)
+	}
+	if notes, err := tools.GetNotes("talk.md", 0); err != nil || notes != "" {
+		t.Errorf("GetNotes on a slide without notes = %q, %v", notes, err)
+	}
+
+	steps := []struct {
+		slide int
+		text  string
+		want  string
+	}{
+		{0, "Welcome everyone", "---\ntheme: default\n---\n\n# Cover\n\n<!--\nWelcome everyone\n-->\n\n---\n\n# Agenda\n\n- One\n\n<!-- Old notes -->\n\n---\nlayout: end\n---\n"},
+		{1, "Walk through --> the list", "---\ntheme: default\n---\n\n# Cover\n\n<!--\nWelcome everyone\n-->\n\n---\n\n# Agenda\n\n- One\n\n<!--\nWalk through --&gt; the list\n-->\n\n---\nlayout: end\n---\n"},
+		{2, "Thanks", "---\ntheme: default\n---\n\n# Cover\n\n<!--\nWelcome everyone\n-->\n\n---\n\n# Agenda\n\n- One\n\n<!--\nWalk through --&gt; the list\n-->\n\n---\nlayout: end\n---\n\n<!--\nThanks\n-->\n"},
+		{0, "", "---\ntheme: default\n---\n\n# Cover\n\n---\n\n# Agenda\n\n- One\n\n<!--\nWalk through --&gt; the list\n-->\n\n---\nlayout: end\n---\n\n<!--\nThanks\n-->\n"},
+	}
+	for _, step := range steps {
+		if err := tools.SetNotes("talk.md", step.slide, step.text); err != nil {
+			t.Fatal(err)
+		}
+		if content, _ := tools.ReadSlides("talk.md"); content != step.want {
+			t.Errorf("after SetNotes(%d, %q):\n%q\nwant\n%q", step.slide, step.text, content, step.want)
+		}
+	}
+
+	if err := tools.SetNotes("talk.md", 3, "x"); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("expected ErrPageOutOfRange, got %v", err)
+	}
+
+	// Decks saved with CRLF keep their line endings
+	if err := tools.SaveSlides("windows.md", "# Cover\r\n\r\n---\r\n\r\n# End\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.SetNotes("windows.md", 1, "Thanks"); err != nil {
+		t.Fatal(err)
+	}
+	if content, want := mustRead(t, tools, "windows.md"), "# Cover\r\n\r\n---\r\n\r\n# End\r\n\r\n<!--\r\nThanks\r\n-->\r\n"; content != want {
+		t.Errorf("CRLF deck after SetNotes:\n%q\nwant\n%q", content, want)
+	}
+}
@@ -0,0 +1,103 @@
+package slidev
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidPath is returned for file names that would leave the workspace or
+// that cannot be used portably
+var ErrInvalidPath = errors.New("invalid path")
+
+// reservedNames are device names Windows will not create files for, with or
+// without an extension. They are refused on every OS so decks stay portable.
+var reservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// ResolvePath returns the absolute path of name inside the workspace root. The
+// name is relative and slash separated; it may not climb out of root, name
+// hidden or reserved files, or reach outside root through a symlink. All file
+// access on behalf of the frontend, the AI or API clients goes through here.
+func ResolvePath(root, name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", fmt.Errorf("%w: no workspace", ErrInvalidPath)
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(absRoot, filepath.FromSlash(name))
+	if !within(absRoot, path) {
+		return "", fmt.Errorf("%w: %q is outside the workspace", ErrInvalidPath, name)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return "", err
+	}
+	// Resolve the longest existing prefix of path; anything below it will be
+	// created inside it and cannot be a link yet
+	for p := path; p != absRoot; p = filepath.Dir(p) {
+		real, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			if !within(realRoot, real) {
+				return "", fmt.Errorf("%w: %q links outside the workspace", ErrInvalidPath, name)
+			}
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if _, err := os.Lstat(p); err == nil {
+			// It exists but does not resolve: a dangling link, which a write
+			// would follow to wherever it points
+			return "", fmt.Errorf("%w: %q is a broken link", ErrInvalidPath, name)
+		}
+	}
+	return path, nil
+}
+
+// validateName checks name lexically, before it touches the file system
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty file name", ErrInvalidPath)
+	}
+	if strings.ContainsRune(name, '\\') || strings.HasPrefix(name, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return fmt.Errorf("%w: %q must be a relative, slash separated path", ErrInvalidPath, name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		switch {
+		case part == "":
+			return fmt.Errorf("%w: %q has an empty path element", ErrInvalidPath, name)
+		case part == "." || part == "..":
+			return fmt.Errorf("%w: %q may not contain %q", ErrInvalidPath, name, part)
+		case strings.HasPrefix(part, "."):
+			return fmt.Errorf("%w: %q names a hidden file", ErrInvalidPath, name)
+		case strings.HasSuffix(part, ".") || strings.HasSuffix(part, " "):
+			return fmt.Errorf("%w: %q may not end in a dot or space", ErrInvalidPath, name)
+		case strings.ContainsFunc(part, func(r rune) bool { return r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"|?*`, r) }):
+			return fmt.Errorf("%w: %q contains a character not allowed in file names", ErrInvalidPath, name)
+		}
+		base, _, _ := strings.Cut(part, ".")
+		if reservedNames[strings.ToLower(strings.TrimRight(base, " "))] {
+			return fmt.Errorf("%w: %q is a reserved name", ErrInvalidPath, name)
+		}
+	}
+	return nil
+}
+
+// within reports whether path is strictly below root
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,141 @@
+package slidev
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "id_rsa"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "talks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "talks", "intro.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	symlinks := map[string]string{
+		"escape":       outside,                                // Directory link out of the workspace
+		"key.md":       filepath.Join(outside, "id_rsa"),       // File link out of the workspace
+		"dangling.md":  filepath.Join(outside, "not-there.md"), // Broken link a write would follow
+		"alias":        filepath.Join(root, "talks"),           // Link that stays inside
+		"alias-rel.md": filepath.Join("talks", "intro.md"),     // Relative link that stays inside
+	}
+	haveSymlinks := true
+	for name, target := range symlinks {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			haveSymlinks = false // e.g. Windows without developer mode
+			break
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ok      bool
+		symlink bool
+	}{
+		{name: "slides.md", ok: true},
+		{name: "new deck.md", ok: true},
+		{name: "talks/intro.md", ok: true},
+		{name: "talks/new.md", ok: true},
+		{name: "missing/dir/deck.md", ok: true},
+		{name: "Übersicht.md", ok: true},
+		{name: "console.md", ok: true},
+
+		{name: ""},
+		{name: "."},
+		{name: ".."},
+		{name: "../secret.md"},
+		{name: "../../.ssh/id_rsa"},
+		{name: "talks/../../secret.md"},
+		{name: "talks/../slides.md"},
+		{name: "talks//intro.md"},
+		{name: "talks/"},
+		{name: "/etc/passwd"},
+		{name: `..\..\secret.md`},
+		{name: `C:\Windows\win.ini`},
+		{name: "C:secret.md"},
+		{name: `\\server\share\deck.md`},
+		{name: ".ssh/id_rsa"},
+		{name: ".studio/trash.md"},
+		{name: "talks/.hidden.md"},
+		{name: "deck.md."},
+		{name: "deck.md "},
+		{name: "deck\x00.md"},
+		{name: "deck\n.md"},
+		{name: "what?.md"},
+		{name: "a|b.md"},
+		{name: "CON"},
+		{name: "con.md"},
+		{name: "talks/Nul.txt"},
+		{name: "COM1.md"},
+		{name: "lpt9"},
+
+		{name: "escape/id_rsa", symlink: true},
+		{name: "escape/new.md", symlink: true},
+		{name: "key.md", symlink: true},
+		{name: "dangling.md", symlink: true},
+		{name: "alias/intro.md", ok: true, symlink: true},
+		{name: "alias-rel.md", ok: true, symlink: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.symlink && !haveSymlinks {
+				t.Skip("symlinks not supported")
+			}
+			path, err := ResolvePath(root, tt.name)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("ResolvePath(%q) failed: %v", tt.name, err)
+				}
+				if want := filepath.Join(root, filepath.FromSlash(tt.name)); path != want {
+					t.Errorf("ResolvePath(%q) = %q, want %q", tt.name, path, want)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("ResolvePath(%q) = %q, %v; want ErrInvalidPath", tt.name, path, err)
+			}
+		})
+	}
+}
+
+func TestToolsRejectEscapes(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "victim.md")
+	if err := os.WriteFile(outside, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(root, outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	tools := NewTools(root)
+	if _, err := tools.ReadSlides(rel); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ReadSlides: expected ErrInvalidPath, got %v", err)
+	}
+	if err := tools.SaveSlides(rel, "overwritten"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("SaveSlides: expected ErrInvalidPath, got %v", err)
+	}
+	if err := tools.DeleteProject(rel); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("DeleteProject: expected ErrInvalidPath, got %v", err)
+	}
+	// CreateProject takes a name rather than a path and sanitizes it
+	if name, err := tools.CreateProjectWithOptions(rel, CreateOptions{}); err != nil || strings.Contains(name, "/") {
+		t.Errorf("CreateProject(%q) = %q, %v", rel, name, err)
+	} else if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+		t.Errorf("sanitized deck not created in the workspace: %v", err)
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "keep me" {
+		t.Errorf("file outside the workspace was modified: %q", data)
+	}
+}
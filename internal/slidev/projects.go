@@ -0,0 +1,304 @@
+package slidev
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrProjectExists is returned when an operation would replace another deck
+var ErrProjectExists = errors.New("project already exists")
+
+// CreateOptions configures a new deck
+type CreateOptions struct {
+	Title      string `json:"title"`      // Cover title; the name when empty
+	Theme      string `json:"theme"`      // Slidev theme; seriph when empty
+	Folder     string `json:"folder"`     // Workspace folder to create the deck in; the top level when empty
+	AutoRename bool   `json:"autoRename"` // Pick "<name> (2).md" instead of failing when the name is taken
+}
+
+// CreateProjectWithOptions creates a deck called name from the default
+// template and returns its file name. The name is sanitized into a file name,
+// so it may contain any characters, but not be empty.
+func (t *Tools) CreateProjectWithOptions(name string, opts CreateOptions) (string, error) {
+	filename, err := SanitizeName(name)
+	if err != nil {
+		return "", err
+	}
+	if folder := strings.Trim(opts.Folder, "/"); folder != "" {
+		if err := checkFolderDepth(folder); err != nil {
+			return "", err
+		}
+		filename = folder + "/" + filename
+	}
+	title := opts.Title
+	if title == "" {
+		title = strings.TrimSpace(strings.TrimSuffix(name, ".md"))
+	}
+	theme := opts.Theme
+	if theme == "" {
+		theme = "seriph"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var path string
+	if opts.AutoRename {
+		filename, path, err = t.freeName(filename, "")
+	} else {
+		path, err = t.resolve(filename)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	// O_EXCL makes the check and the creation one step
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrProjectExists, filename)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(defaultDeck(title, theme)); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return filename, f.Close()
+}
+
+// SanitizeName turns a user supplied deck name into a file name that
+// ResolvePath accepts: characters not allowed in file names become dashes,
+// leading dots and trailing dots and spaces are dropped, reserved device
+// names get a suffix, and .md is appended. It fails when nothing is left.
+func SanitizeName(name string) (string, error) {
+	base := strings.TrimSuffix(strings.TrimSpace(name), ".md")
+	base = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '-'
+		}
+		return r
+	}, base)
+	base = strings.Join(strings.Fields(base), " ")
+	base = regexp.MustCompile(`-{2,}`).ReplaceAllString(base, "-")
+	base = strings.TrimLeft(base, ". -")
+	base = strings.TrimRight(base, ". ")
+	if r := []rune(base); len(r) > maxNameLength {
+		base = strings.TrimRight(string(r[:maxNameLength]), ". ")
+	}
+	if base == "" {
+		return "", fmt.Errorf("%w: %q has no usable characters", ErrInvalidPath, name)
+	}
+	if stem, rest, found := strings.Cut(base, "."); reservedNames[strings.ToLower(stem)] {
+		base = stem + "_"
+		if found {
+			base += "." + rest
+		}
+	}
+	return base + ".md", nil
+}
+
+// maxNameLength bounds sanitized names, leaving room for suffixes such as
+// " (copy 2)" within common file name limits
+const maxNameLength = 100
+
+// Slugify turns a name into a lowercase, dash separated identifier such as
+// "q3-review", or "" when it has no letters or digits
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// withDeckExt appends .md to names given without it
+func withDeckExt(name string) string {
+	if !strings.HasSuffix(name, ".md") {
+		return name + ".md"
+	}
+	return name
+}
+
+// RenameProject gives a deck a new file name in the same folder
+func (t *Tools) RenameProject(filename, newName string) error {
+	if strings.Contains(newName, "/") {
+		return fmt.Errorf("%w: %q is not a file name, use MoveProject to change folders", ErrInvalidPath, newName)
+	}
+	return t.move(filename, path.Join(path.Dir(filename), withDeckExt(newName)))
+}
+
+// MoveProject moves a deck into another folder of the workspace, "" being the
+// top level, and returns its new file name. Folders are at most
+// maxFolderDepth deep, so that ListProjects finds the deck.
+func (t *Tools) MoveProject(filename, toFolder string) (string, error) {
+	to := path.Base(filename)
+	if toFolder = strings.Trim(toFolder, "/"); toFolder != "" {
+		if err := checkFolderDepth(toFolder); err != nil {
+			return "", err
+		}
+		to = toFolder + "/" + to
+	}
+	return to, t.move(filename, to)
+}
+
+// checkFolderDepth refuses folders deeper than maxFolderDepth, whose decks
+// ListProjects would not find
+func checkFolderDepth(folder string) error {
+	if strings.Count(folder, "/") >= maxFolderDepth {
+		return fmt.Errorf("%w: folder %s is too deep", ErrInvalidPath, folder)
+	}
+	return nil
+}
+
+// move renames the deck from to to, together with its sidecar data, and
+// tells OnRename
+func (t *Tools) move(from, to string) error {
+	if err := t.moveLocked(from, to); err != nil {
+		return err
+	}
+	if t.OnRename != nil && from != to {
+		t.OnRename(from, to)
+	}
+	return nil
+}
+
+func (t *Tools) moveLocked(from, to string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	src, err := t.resolve(from)
+	if err != nil {
+		return err
+	}
+	dst, err := t.resolve(to)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return nil
+	}
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !srcInfo.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a deck file", from)
+	}
+	// On case-insensitive file systems a change of case finds the deck itself
+	if dstInfo, err := os.Lstat(dst); err == nil && !os.SameFile(srcInfo, dstInfo) {
+		return fmt.Errorf("%w: %s", ErrProjectExists, to)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	oldData, newData := t.deckDataDir(from), t.deckDataDir(to)
+	if _, err := os.Stat(oldData); err == nil {
+		if err := os.RemoveAll(newData); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(newData), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(oldData, newData); err != nil {
+			return fmt.Errorf("failed to move the data of %s: %w", from, err)
+		}
+	}
+	return nil
+}
+
+// DuplicateProject copies a deck to newName in the same folder and returns the
+// copy's file name. Without a name the copy is called "<name> (copy).md". The
+// copy starts without the original's history.
+func (t *Tools) DuplicateProject(filename, newName string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	src, err := t.resolve(filename)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Lstat(src)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a deck file", filename)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+
+	var name, dst string
+	if newName == "" {
+		name, dst, err = t.freeName(filename, "copy")
+	} else if strings.Contains(newName, "/") {
+		err = fmt.Errorf("%w: %q is not a file name", ErrInvalidPath, newName)
+	} else {
+		name = path.Join(path.Dir(filename), withDeckExt(newName))
+		dst, err = t.resolve(name)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrProjectExists, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// freeName returns name, or "<name> (label).md", "<name> (label 2).md" and
+// so on, whichever no file in the workspace uses yet, along with its path.
+// Without a label the variants are "<name> (2).md", "<name> (3).md"...
+func (t *Tools) freeName(name, label string) (string, string, error) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; ; i++ {
+		target, err := t.resolve(candidate)
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			return candidate, target, nil
+		}
+		if label == "" {
+			candidate = fmt.Sprintf("%s (%d)%s", base, i+1, ext)
+		} else if i == 1 {
+			candidate = fmt.Sprintf("%s (%s)%s", base, label, ext)
+		} else {
+			candidate = fmt.Sprintf("%s (%s %d)%s", base, label, i, ext)
+		}
+	}
+}
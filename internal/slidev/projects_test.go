@@ -0,0 +1,182 @@
+package slidev
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenameMoveDuplicate(t *testing.T) {
+	root := t.TempDir()
+	tools := NewTools(root)
+	var renames [][2]string
+	tools.OnRename = func(oldName, newName string) {
+		renames = append(renames, [2]string{oldName, newName})
+	}
+	for _, name := range []string{"talk.md", "other.md"} {
+		if err := tools.CreateProject(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history := filepath.Join(tools.deckDataDir("talk.md"), "history.json")
+	if err := os.MkdirAll(filepath.Dir(history), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(history, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tools.RenameProject("talk.md", "other"); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists renaming onto another deck, got %v", err)
+	}
+	if err := tools.RenameProject("talk.md", "../escape"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath, got %v", err)
+	}
+	if err := tools.RenameProject("talk.md", "keynote"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tools.deckDataDir("keynote.md"), "history.json")); err != nil {
+		t.Errorf("deck data did not follow the rename: %v", err)
+	}
+
+	if _, err := tools.MoveProject("keynote.md", "2024/q3"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath moving into a nested folder, got %v", err)
+	}
+	name, err := tools.MoveProject("keynote.md", "q3")
+	if err != nil || name != "q3/keynote.md" {
+		t.Fatalf("MoveProject = %q, %v", name, err)
+	}
+	if _, err := os.Stat(filepath.Join(tools.deckDataDir(name), "history.json")); err != nil {
+		t.Errorf("deck data did not follow the move: %v", err)
+	}
+	if err := tools.RenameProject(name, "final.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "q3", "final.md")); err != nil {
+		t.Errorf("rename left the folder: %v", err)
+	}
+
+	want := [][2]string{{"talk.md", "keynote.md"}, {"keynote.md", "q3/keynote.md"}, {"q3/keynote.md", "q3/final.md"}}
+	if len(renames) != len(want) {
+		t.Fatalf("OnRename calls = %v, want %v", renames, want)
+	}
+	for i := range want {
+		if renames[i] != want[i] {
+			t.Errorf("OnRename call %d = %v, want %v", i, renames[i], want[i])
+		}
+	}
+
+	// Decks two folders deep are not listed
+	if err := os.MkdirAll(filepath.Join(root, "2024", "q3"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "2024", "q3", "deep.md"), []byte("# Deep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	projects, err := tools.ListProjects()
+	if err != nil || len(projects) != 2 {
+		t.Fatalf("ListProjects = %v, %v", projects, err)
+	}
+	if projects[0].Name != "other.md" || projects[1].Name != "q3/final.md" {
+		t.Errorf("unexpected projects %v", projects)
+	}
+
+	copyName, err := tools.DuplicateProject("other.md", "")
+	if err != nil || copyName != "other (copy).md" {
+		t.Fatalf("DuplicateProject = %q, %v", copyName, err)
+	}
+	if copyName, _ := tools.DuplicateProject("other.md", ""); copyName != "other (copy 2).md" {
+		t.Errorf("second copy named %q", copyName)
+	}
+	if _, err := tools.DuplicateProject("other.md", "other (copy)"); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists, got %v", err)
+	}
+	original, _ := tools.ReadSlides("other.md")
+	duplicate, _ := tools.ReadSlides(copyName)
+	if original != duplicate {
+		t.Errorf("copy differs from the original")
+	}
+}
+
+func TestServerRetargetWhenIdle(t *testing.T) {
+	s := NewServer()
+	if url, err := s.Retarget("a.md", "b.md"); url != "" || err != nil {
+		t.Errorf("Retarget on a stopped server = %q, %v", url, err)
+	}
+	if s.CurrentFile() != "" {
+		t.Errorf("stopped server reports file %q", s.CurrentFile())
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"talk", "talk.md"},
+		{"talk.md", "talk.md"},
+		{"  Q3 Review  ", "Q3 Review.md"},
+		{"Q3: Review / Final?", "Q3- Review - Final-.md"},
+		{"../../.ssh/id_rsa", "ssh-id_rsa.md"},
+		{".hidden", "hidden.md"},
+		{"notes...", "notes.md"},
+		{"tab\there", "tab-here.md"},
+		{"CON", "CON_.md"},
+		{"lpt1.backup", "lpt1_.backup.md"},
+		{"Übersicht", "Übersicht.md"},
+		{"", ""},
+		{" ... ", ""},
+		{"///", ""},
+	}
+	for _, tt := range tests {
+		got, err := SanitizeName(tt.name)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("SanitizeName(%q) = %q, %v; want ErrInvalidPath", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("SanitizeName(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+		if err == nil {
+			if err := validateName(got); err != nil {
+				t.Errorf("SanitizeName(%q) = %q, which ResolvePath refuses: %v", tt.name, got, err)
+			}
+		}
+	}
+
+	if got := Slugify("  Q3 Review: Final! "); got != "q3-review-final" {
+		t.Errorf("Slugify = %q", got)
+	}
+}
+
+func TestCreateProjectWithOptions(t *testing.T) {
+	root := t.TempDir()
+	tools := NewTools(root)
+
+	name, err := tools.CreateProjectWithOptions("Q3: Review", CreateOptions{Title: "Quarterly Review", Theme: "default", Folder: "2024"})
+	if err != nil || name != "2024/Q3- Review.md" {
+		t.Fatalf("CreateProjectWithOptions = %q, %v", name, err)
+	}
+	content, _ := tools.ReadSlides(name)
+	if !strings.Contains(content, "theme: default") || !strings.Contains(content, "# Quarterly Review") {
+		t.Errorf("title or theme not applied:\n%s", content)
+	}
+
+	if err := tools.SaveSlides("talk.md", "mine"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.CreateProject("talk"); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists, got %v", err)
+	}
+	if content, _ := tools.ReadSlides("talk.md"); content != "mine" {
+		t.Errorf("existing deck was overwritten: %q", content)
+	}
+	for _, want := range []string{"talk (2).md", "talk (3).md"} {
+		if name, err := tools.CreateProjectWithOptions("talk", CreateOptions{AutoRename: true}); err != nil || name != want {
+			t.Errorf("auto-renamed create = %q, %v; want %q", name, err, want)
+		}
+	}
+}
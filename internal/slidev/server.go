@@ -18,6 +18,9 @@ import (
 )
 
 type Server struct {
+	// Logs receives Slidev's output and the server's own messages; os.Stdout
+	// when nil. Set it before Start.
+	Logs          io.Writer
 	cmd           *exec.Cmd
 	url           string
 	running       bool
@@ -28,6 +31,7 @@ type Server struct {
 	generation    int
 	port          int
 	currentFile   string
+	dir           string
 	stdinW        *io.PipeWriter
 	slidevNodePID int
 }
@@ -41,6 +45,19 @@ func NewServer() *Server {
 	return &Server{}
 }
 
+// logWriter returns where the server's logs go
+func (s *Server) logWriter() io.Writer {
+	if s.Logs == nil {
+		return os.Stdout
+	}
+	return s.Logs
+}
+
+// logf writes a message to the server's logs
+func (s *Server) logf(format string, args ...any) {
+	fmt.Fprintf(s.logWriter(), format, args...)
+}
+
 // getFreePort returns a free port to use
 func getFreePort() (int, error) {
 	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
@@ -55,11 +72,64 @@ func getFreePort() (int, error) {
 	return l.Addr().(*net.TCPAddr).Port, nil
 }
 
+// slidevCommand builds the command running the Slidev CLI with args. The
+// bundled Node and Slidev are used when the app ships with them, npx
+// otherwise. The command line is written to logs, which may be nil.
+func slidevCommand(ctx context.Context, logs io.Writer, args ...string) (*exec.Cmd, error) {
+	if logs == nil {
+		logs = io.Discard
+	}
+	// Runtime detection for Slidev and Node
+	nodeExe := "node"
+	slidevBin := ""
+	isProduction := false
+
+	// Check for bundled resources (Production)
+	exePath, _ := os.Executable()
+	appDir := filepath.Dir(exePath)
+	bundledResources := filepath.Join(appDir, "resources")
+
+	// If resources folder exists, we assume production
+	if _, err := os.Stat(bundledResources); err == nil {
+		isProduction = true
+		// Use bundled node if exists
+		bundledNode := filepath.Join(bundledResources, "node", "node.exe")
+		if _, err := os.Stat(bundledNode); err == nil {
+			nodeExe = bundledNode
+		}
+
+		// Use bundled slidev from node_modules (located in app root for theme resolution)
+		bundledSlidev := filepath.Join(appDir, "node_modules", "@slidev", "cli", "bin", "slidev.mjs")
+		if _, err := os.Stat(bundledSlidev); err == nil {
+			slidevBin = bundledSlidev
+		}
+	}
+
+	// In production mode, we MUST have bundled resources - no fallback to npx
+	if isProduction && slidevBin == "" {
+		return nil, fmt.Errorf("production mode detected but bundled Slidev not found at %s. Please reinstall the application", filepath.Join(appDir, "node_modules", "@slidev", "cli", "bin", "slidev.mjs"))
+	}
+
+	if slidevBin != "" {
+		// Production: run node [slidev.mjs]
+		nodeArgs := append([]string{slidevBin}, args...)
+		fmt.Fprintf(logs, "Starting Bundled Slidev: %s %s\n", nodeExe, strings.Join(nodeArgs, " "))
+		return exec.CommandContext(ctx, nodeExe, nodeArgs...), nil
+	}
+	// Development: fallback to npx
+	npxArgs := append([]string{"--yes", "@slidev/cli"}, args...)
+	fmt.Fprintf(logs, "Starting Slidev via npx: npx %s\n", strings.Join(npxArgs, " "))
+	return exec.CommandContext(ctx, "npx", npxArgs...), nil
+}
+
 // Start starts the slidev server in the given directory for a specific file
 func (s *Server) Start(dir string, filename string) (string, error) {
 	if filename == "" {
 		filename = "slides.md"
 	}
+	if _, err := ResolvePath(dir, filename); err != nil {
+		return "", err
+	}
 
 	s.mu.Lock()
 	// If already running with same file, return URL
@@ -86,6 +156,7 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 	gen := s.generation
 	s.starting = true
 	s.currentFile = filename
+	s.dir = dir
 	s.slidevNodePID = 0
 	s.startCh = make(chan startResult, 1)
 	s.mu.Unlock()
@@ -115,55 +186,13 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 		}
 	}
 
-	// Runtime detection for Slidev and Node
-	nodeExe := "node"
-	slidevBin := ""
-	isProduction := false
-
-	// Check for bundled resources (Production)
-	exePath, _ := os.Executable()
-	appDir := filepath.Dir(exePath)
-	bundledResources := filepath.Join(appDir, "resources")
-
-	// If resources folder exists, we assume production
-	if _, err := os.Stat(bundledResources); err == nil {
-		isProduction = true
-		// Use bundled node if exists
-		bundledNode := filepath.Join(bundledResources, "node", "node.exe")
-		if _, err := os.Stat(bundledNode); err == nil {
-			nodeExe = bundledNode
-		}
-
-		// Use bundled slidev from node_modules (located in app root for theme resolution)
-		bundledSlidev := filepath.Join(appDir, "node_modules", "@slidev", "cli", "bin", "slidev.mjs")
-		if _, err := os.Stat(bundledSlidev); err == nil {
-			slidevBin = bundledSlidev
-		}
-	}
-
-	// In production mode, we MUST have bundled resources - no fallback to npx
-	if isProduction && slidevBin == "" {
-		err := fmt.Errorf("production mode detected but bundled Slidev not found at %s. Please reinstall the application", filepath.Join(appDir, "node_modules", "@slidev", "cli", "bin", "slidev.mjs"))
+	cmd, err := slidevCommand(ctx, s.logWriter(), filename, "--port", strconv.Itoa(port))
+	if err != nil {
 		cancel()
 		complete("", err)
 		return "", err
 	}
 
-	portStr := strconv.Itoa(port)
-	var cmd *exec.Cmd
-
-	if slidevBin != "" {
-		// Production: run node [slidev.mjs]
-		args := []string{slidevBin, filename, "--port", portStr}
-		fmt.Printf("Starting Bundled Slidev: %s %s\n", nodeExe, strings.Join(args, " "))
-		cmd = exec.CommandContext(ctx, nodeExe, args...)
-	} else {
-		// Development: fallback to npx
-		npxArgs := []string{"--yes", "@slidev/cli", filename, "--port", portStr}
-		fmt.Printf("Starting Slidev via npx: npx %s\n", strings.Join(npxArgs, " "))
-		cmd = exec.CommandContext(ctx, "npx", npxArgs...)
-	}
-
 	cmd.SysProcAttr = getSysProcAttr()
 	cmd.Dir = dir
 
@@ -191,14 +220,14 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 		return "", startErr
 	}
 
-	fmt.Printf("[Server] Slidev process started, pid=%d\n", cmd.Process.Pid)
+	s.logf("[Server] Slidev process started, pid=%d\n", cmd.Process.Pid)
 
 	go func() {
 		err := cmd.Wait()
 		if err != nil {
-			fmt.Printf("[Server] Slidev process exited (pid=%d) with error: %v\n", cmd.Process.Pid, err)
+			s.logf("[Server] Slidev process exited (pid=%d) with error: %v\n", cmd.Process.Pid, err)
 		} else {
-			fmt.Printf("[Server] Slidev process exited cleanly (pid=%d)\n", cmd.Process.Pid)
+			s.logf("[Server] Slidev process exited cleanly (pid=%d)\n", cmd.Process.Pid)
 		}
 		// Note: do not clear running/url here; on Windows `npx` may exit while the Node server keeps running.
 		// We rely on Stop() to terminate the process tree and clear state.
@@ -224,7 +253,7 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 		scanner := bufio.NewScanner(stdout)
 		for scanner.Scan() {
 			line := scanner.Text()
-			fmt.Printf("[Slidev Log] %s\n", line)
+			s.logf("[Slidev Log] %s\n", line)
 
 			// Capture node PID for reliable stop even if npx exits.
 			if m := nodePIDRe.FindStringSubmatch(line); len(m) > 1 {
@@ -232,7 +261,7 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 					s.mu.Lock()
 					if s.slidevNodePID == 0 {
 						s.slidevNodePID = pid
-						fmt.Printf("[Server] Captured Slidev node pid=%d\n", pid)
+						s.logf("[Server] Captured Slidev node pid=%d\n", pid)
 					}
 					s.mu.Unlock()
 				}
@@ -263,8 +292,8 @@ func (s *Server) Start(dir string, filename string) (string, error) {
 	case detectedPort := <-portChan:
 		// Prefer using Slidev's printed host (localhost). This is what works in your manual CLI test.
 		chosenURL := strings.TrimRight("http://localhost:"+detectedPort, "/")
-		fmt.Printf("[Server] Slidev detected ready at %s\n", chosenURL)
-		fmt.Printf("[Server] Hint: if browser can't open %s, try http://127.0.0.1:%s/ or http://[::1]:%s/\n", chosenURL, detectedPort, detectedPort)
+		s.logf("[Server] Slidev detected ready at %s\n", chosenURL)
+		s.logf("[Server] Hint: if browser can't open %s, try http://127.0.0.1:%s/ or http://[::1]:%s/\n", chosenURL, detectedPort, detectedPort)
 
 		complete(chosenURL, nil)
 		return chosenURL, nil
@@ -309,7 +338,7 @@ func (s *Server) Stop() error {
 		close(ch)
 	}
 
-	fmt.Printf("[Server] Stop() called; process will be terminated if running\n")
+	s.logf("[Server] Stop() called; process will be terminated if running\n")
 
 	if cancel != nil {
 		cancel()
@@ -333,6 +362,33 @@ func (s *Server) Stop() error {
 	return nil
 }
 
+// CurrentFile returns the deck the server is running or starting for, "" when
+// it is stopped
+func (s *Server) CurrentFile() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running && !s.starting {
+		return ""
+	}
+	return s.currentFile
+}
+
+// Retarget follows a deck that was renamed or moved from oldFile to newFile.
+// Slidev watches the path it was started with, so a server for oldFile is
+// restarted on newFile. It returns the new URL, or "" when the server was
+// not serving oldFile.
+func (s *Server) Retarget(oldFile, newFile string) (string, error) {
+	s.mu.Lock()
+	active := (s.running || s.starting) && s.currentFile == oldFile
+	dir := s.dir
+	s.mu.Unlock()
+	if !active {
+		return "", nil
+	}
+	_ = s.Stop()
+	return s.Start(dir, newFile)
+}
+
 func (s *Server) GetURL() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -0,0 +1,20 @@
+package slidev
+
+import "path/filepath"
+
+// SidecarDir is the hidden directory in the workspace where the app keeps
+// data about decks, such as the trash. ResolvePath refuses hidden names, so
+// decks can never be read or written inside it.
+const SidecarDir = ".studio"
+
+// sidecarPath returns a path in the workspace's sidecar directory. The
+// caller must hold t.mu.
+func (t *Tools) sidecarPath(elem ...string) string {
+	return filepath.Join(append([]string{t.WorkingDir, SidecarDir}, elem...)...)
+}
+
+// deckDataDir returns the sidecar directory holding a deck's metadata and
+// history. It moves with the deck. The caller must hold t.mu.
+func (t *Tools) deckDataDir(filename string) string {
+	return t.sidecarPath("decks", filepath.FromSlash(filename))
+}
@@ -0,0 +1,442 @@
+package slidev
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// builtinTemplates ship with the app. A user template with the same ID
+// replaces a builtin one.
+//
+//go:embed templates
+var builtinTemplates embed.FS
+
+// ErrTemplateNotFound is returned for template IDs that do not exist
+var ErrTemplateNotFound = errors.New("template not found")
+
+// DefaultTemplate is the template new decks start from unless another is chosen
+const DefaultTemplate = "default"
+
+// A template is a directory holding template.json, a slides.md with
+// {{placeholders}} and any asset files the deck references
+const (
+	templateMetaFile = "template.json"
+	templateDeckFile = "slides.md"
+)
+
+// Template describes a deck template
+type Template struct {
+	ID          string             `json:"id"` // Directory name
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Theme       string             `json:"theme,omitempty"` // Value of {{theme}} unless given
+	Variables   []TemplateVariable `json:"variables,omitempty"`
+	Assets      []string           `json:"assets,omitempty"` // Slash separated paths of the asset files
+	Builtin     bool               `json:"builtin"`
+}
+
+// TemplateVariable is a placeholder the user fills in when creating a deck
+type TemplateVariable struct {
+	Name    string `json:"name"`
+	Label   string `json:"label,omitempty"`
+	Default string `json:"default,omitempty"`
+}
+
+// Placeholders that are always available: title (from the file name unless
+// given), theme, date and assetDir, the folder the template's assets are
+// copied to
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z][A-Za-z0-9_]*)\s*\}\}`)
+
+var templateIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Templates is the template gallery: the builtin templates plus the user's
+// templates in Dir
+type Templates struct {
+	Dir string
+}
+
+// NewTemplates returns the gallery of templates stored in dir
+func NewTemplates(dir string) *Templates {
+	return &Templates{Dir: dir}
+}
+
+// List returns all templates, sorted by name
+func (g *Templates) List() ([]Template, error) {
+	byID := map[string]Template{}
+	builtin, _ := fs.ReadDir(builtinTemplates, "templates")
+	for _, d := range builtin {
+		if tpl, err := readTemplate(g.source(d.Name(), true), d.Name()); err == nil {
+			tpl.Builtin = true
+			byID[tpl.ID] = tpl
+		}
+	}
+	user, err := os.ReadDir(g.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, d := range user {
+		if !d.IsDir() || !templateIDPattern.MatchString(d.Name()) {
+			continue
+		}
+		if tpl, err := readTemplate(g.source(d.Name(), false), d.Name()); err == nil {
+			byID[tpl.ID] = tpl
+		}
+	}
+
+	templates := make([]Template, 0, len(byID))
+	for _, tpl := range byID {
+		templates = append(templates, tpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+// Get returns a template by ID along with the files it is made of
+func (g *Templates) Get(id string) (Template, fs.FS, error) {
+	if !templateIDPattern.MatchString(id) {
+		return Template{}, nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, id)
+	}
+	if g.Dir != "" {
+		if _, err := os.Stat(filepath.Join(g.Dir, id, templateMetaFile)); err == nil {
+			src := g.source(id, false)
+			tpl, err := readTemplate(src, id)
+			return tpl, src, err
+		}
+	}
+	src := g.source(id, true)
+	tpl, err := readTemplate(src, id)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Template{}, nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, id)
+	}
+	tpl.Builtin = true
+	return tpl, src, err
+}
+
+// Delete removes a user template. Builtin templates cannot be deleted.
+func (g *Templates) Delete(id string) error {
+	if !templateIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrTemplateNotFound, id)
+	}
+	dir := filepath.Join(g.Dir, id)
+	if _, err := os.Stat(dir); err != nil {
+		if _, err := fs.Stat(builtinTemplates, "templates/"+id); err == nil {
+			return fmt.Errorf("template %q is builtin and cannot be deleted", id)
+		}
+		return fmt.Errorf("%w: %q", ErrTemplateNotFound, id)
+	}
+	return os.RemoveAll(dir)
+}
+
+func (g *Templates) source(id string, builtin bool) fs.FS {
+	if builtin {
+		sub, _ := fs.Sub(builtinTemplates, "templates/"+id)
+		return sub
+	}
+	return os.DirFS(filepath.Join(g.Dir, id))
+}
+
+func readTemplate(src fs.FS, id string) (Template, error) {
+	var tpl Template
+	data, err := fs.ReadFile(src, templateMetaFile)
+	if err != nil {
+		return tpl, err
+	}
+	if err := json.Unmarshal(data, &tpl); err != nil {
+		return tpl, fmt.Errorf("invalid %s of template %q: %w", templateMetaFile, id, err)
+	}
+	if _, err := fs.Stat(src, templateDeckFile); err != nil {
+		return tpl, fmt.Errorf("template %q has no %s: %w", id, templateDeckFile, err)
+	}
+	tpl.ID = id
+	if tpl.Name == "" {
+		tpl.Name = id
+	}
+	tpl.Assets, err = templateAssets(src)
+	return tpl, err
+}
+
+// templateAssets lists the files of a template other than its definition
+func templateAssets(src fs.FS) ([]string, error) {
+	var assets []string
+	err := fs.WalkDir(src, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && p != templateMetaFile && p != templateDeckFile {
+			assets = append(assets, p)
+		}
+		return nil
+	})
+	return assets, err
+}
+
+// renderTemplate replaces the {{placeholders}} of markdown that have a value.
+// Others, such as Vue interpolations in the slides, are left alone.
+func renderTemplate(markdown string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(markdown, func(m string) string {
+		name := placeholderPattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// templateVars returns the values of every placeholder of tpl for a deck
+// called filename, the user's values taking precedence
+func templateVars(tpl Template, filename string, vars map[string]string) map[string]string {
+	base := strings.TrimSuffix(filename, ".md")
+	values := map[string]string{
+		"title":    path.Base(base),
+		"theme":    tpl.Theme,
+		"date":     time.Now().Format("2006-01-02"),
+		"assetDir": "./" + path.Base(base) + "-assets",
+	}
+	for _, v := range tpl.Variables {
+		if v.Default != "" {
+			values[v.Name] = v.Default
+		}
+	}
+	for k, v := range vars {
+		if k != "assetDir" { // Where assets go is not up to the user
+			values[k] = v
+		}
+	}
+	return values
+}
+
+// assetDirFor returns the workspace folder receiving the assets of a deck
+// created from a template, e.g. "talks/intro-assets" for "talks/intro.md"
+func assetDirFor(filename string) string {
+	return strings.TrimSuffix(filename, ".md") + "-assets"
+}
+
+// CreateProjectFromTemplate creates a deck from a template of the gallery,
+// filling in its placeholders from vars, and copies the template's assets
+// next to it. The last element of name is sanitized as in
+// CreateProjectWithOptions; anything before it is the folder. It returns the
+// deck's file name.
+func (t *Tools) CreateProjectFromTemplate(gallery *Templates, name, templateID string, vars map[string]string) (string, error) {
+	folder, base := path.Split(name)
+	filename, err := SanitizeName(base)
+	if err != nil {
+		return "", err
+	}
+	filename = folder + filename
+	tpl, src, err := gallery.Get(templateID)
+	if err != nil {
+		return "", err
+	}
+	markdown, err := fs.ReadFile(src, templateDeckFile)
+	if err != nil {
+		return "", err
+	}
+	content := renderTemplate(string(markdown), templateVars(tpl, filename, vars))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	deckPath, err := t.resolve(filename)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(deckPath), 0755); err != nil {
+		return "", err
+	}
+	// O_EXCL makes the check and the creation one step; the deck is removed
+	// again if the rest fails
+	f, err := os.OpenFile(deckPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrProjectExists, filename)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := t.copyTemplateAssets(tpl, src, filename); err != nil {
+		f.Close()
+		os.Remove(deckPath)
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(deckPath)
+		return "", err
+	}
+	return filename, f.Close()
+}
+
+// copyTemplateAssets copies a template's assets into the asset folder of the
+// deck filename, which must not exist yet. The caller must hold t.mu.
+func (t *Tools) copyTemplateAssets(tpl Template, src fs.FS, filename string) error {
+	if len(tpl.Assets) == 0 {
+		return nil
+	}
+	assetDir, err := t.resolve(assetDirFor(filename))
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(assetDir, 0755); os.IsExist(err) {
+		return fmt.Errorf("%w: %s", ErrProjectExists, assetDirFor(filename))
+	} else if err != nil {
+		return err
+	}
+	for _, asset := range tpl.Assets {
+		if err := copyFromFS(src, asset, filepath.Join(assetDir, filepath.FromSlash(asset))); err != nil {
+			os.RemoveAll(assetDir)
+			return fmt.Errorf("failed to copy asset %s: %w", asset, err)
+		}
+	}
+	return nil
+}
+
+// SaveProjectAsTemplate adds a deck to the gallery as a new template named
+// name. The deck's asset folder, if it has one, becomes the template's assets.
+func (t *Tools) SaveProjectAsTemplate(gallery *Templates, filename, name, description string) (Template, error) {
+	if strings.TrimSpace(name) == "" {
+		return Template{}, fmt.Errorf("template name is required")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	deckPath, err := t.resolve(filename)
+	if err != nil {
+		return Template{}, err
+	}
+	markdown, err := os.ReadFile(deckPath)
+	if err != nil {
+		return Template{}, err
+	}
+
+	id, err := gallery.newID(name)
+	if err != nil {
+		return Template{}, err
+	}
+	dir := filepath.Join(gallery.Dir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Template{}, err
+	}
+	fail := func(err error) (Template, error) {
+		os.RemoveAll(dir)
+		return Template{}, err
+	}
+
+	// References to the deck's own asset folder point at the copy's instead
+	content := string(markdown)
+	assetDir := assetDirFor(filename)
+	if src, err := t.resolve(assetDir); err == nil {
+		if info, err := os.Stat(src); err == nil && info.IsDir() {
+			assets := os.DirFS(src)
+			files, err := templateAssets(assets)
+			if err != nil {
+				return fail(err)
+			}
+			for _, asset := range files {
+				if err := copyFromFS(assets, asset, filepath.Join(dir, filepath.FromSlash(asset))); err != nil {
+					return fail(err)
+				}
+			}
+			local := path.Base(assetDir)
+			content = strings.ReplaceAll(content, "./"+local, "{{assetDir}}")
+			content = strings.ReplaceAll(content, local+"/", "{{assetDir}}/")
+		}
+	}
+
+	tpl := Template{ID: id, Name: name, Description: description, Theme: headmatterTheme(content)}
+	if tpl.Theme != "" {
+		content = strings.Replace(content, "theme: "+tpl.Theme, "theme: {{theme}}", 1)
+	}
+	meta, err := json.MarshalIndent(tpl, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, templateDeckFile), []byte(content), 0644); err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, templateMetaFile), meta, 0644); err != nil {
+		return fail(err)
+	}
+	tpl, _, err = gallery.Get(id)
+	return tpl, err
+}
+
+// newID derives an unused template ID from a display name
+func (g *Templates) newID(name string) (string, error) {
+	slug := Slugify(name)
+	if slug == "" {
+		slug = "template"
+	}
+	id := slug
+	for i := 2; ; i++ {
+		_, userErr := os.Stat(filepath.Join(g.Dir, id))
+		_, builtinErr := fs.Stat(builtinTemplates, "templates/"+id)
+		if os.IsNotExist(userErr) && builtinErr != nil {
+			return id, nil
+		}
+		if userErr != nil && !os.IsNotExist(userErr) {
+			return "", userErr
+		}
+		id = fmt.Sprintf("%s-%d", slug, i)
+	}
+}
+
+// headmatterTheme returns the theme set in a deck's headmatter
+func headmatterTheme(content string) string {
+	m := regexp.MustCompile(`(?s)^---\n(.*?)\n---`).FindStringSubmatch(strings.ReplaceAll(content, "\r\n", "\n"))
+	if m == nil {
+		return ""
+	}
+	if t := regexp.MustCompile(`(?m)^theme:\s*(\S+)`).FindStringSubmatch(m[1]); t != nil {
+		return t[1]
+	}
+	return ""
+}
+
+func copyFromFS(src fs.FS, name, dst string) error {
+	in, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// defaultDeck renders the builtin default template, which CreateProject and
+// CreateDeck start from
+func defaultDeck(title, theme string) string {
+	markdown, err := fs.ReadFile(builtinTemplates, "templates/"+DefaultTemplate+"/"+templateDeckFile)
+	if err != nil {
+		panic(err) // Embedded at build time
+	}
+	return renderTemplate(string(markdown), map[string]string{"title": title, "theme": theme})
+}
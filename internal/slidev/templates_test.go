@@ -0,0 +1,113 @@
+package slidev
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	gallery := NewTemplates(t.TempDir())
+	root := t.TempDir()
+	tools := NewTools(root)
+
+	templates, err := gallery.List()
+	if err != nil || len(templates) != 1 || templates[0].ID != DefaultTemplate || !templates[0].Builtin {
+		t.Fatalf("List = %v, %v", templates, err)
+	}
+
+	// A team template with a placeholder, an asset and a Vue interpolation
+	// that must survive rendering
+	dir := filepath.Join(gallery.Dir, "team")
+	if err := os.MkdirAll(filepath.Join(dir, "img"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		templateMetaFile: `{"name": "Team", "theme": "default", "variables": [{"name": "speaker", "default": "Someone"}]}`,
+		templateDeckFile: "---\ntheme: {{theme}}\n---\n\n# {{ title }}\n\nBy {{speaker}}\n\n![logo]({{assetDir}}/img/logo.png)\n\n{{ $slidev.nav.currentPage }}\n",
+		"img/logo.png":   "PNG",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tpl, _, err := gallery.Get("team")
+	if err != nil || tpl.Name != "Team" || len(tpl.Assets) != 1 || tpl.Assets[0] != "img/logo.png" {
+		t.Fatalf("Get = %+v, %v", tpl, err)
+	}
+
+	name, err := tools.CreateProjectFromTemplate(gallery, "review", "team", map[string]string{"title": "Q3 Review"})
+	if err != nil || name != "review.md" {
+		t.Fatalf("CreateProjectFromTemplate = %q, %v", name, err)
+	}
+	content, _ := tools.ReadSlides(name)
+	for _, want := range []string{"theme: default", "# Q3 Review", "By Someone", "](./review-assets/img/logo.png)", "{{ $slidev.nav.currentPage }}"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("deck is missing %q:\n%s", want, content)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "review-assets", "img", "logo.png")); err != nil || string(data) != "PNG" {
+		t.Errorf("asset not copied: %q, %v", data, err)
+	}
+	if _, err := tools.CreateProjectFromTemplate(gallery, "review", "team", nil); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists, got %v", err)
+	}
+	if name, err := tools.CreateProjectFromTemplate(gallery, "Q4: plans?", "team", nil); err != nil || name != "Q4- plans-.md" {
+		t.Errorf("name not sanitized: %q, %v", name, err)
+	}
+	// A deck claimed first wins, without its assets being touched
+	if err := os.WriteFile(filepath.Join(root, "taken.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.CreateProjectFromTemplate(gallery, "taken", "team", nil); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "taken-assets")); !os.IsNotExist(err) {
+		t.Errorf("assets copied for an existing deck: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "clash-assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.CreateProjectFromTemplate(gallery, "clash", "team", nil); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected ErrProjectExists for an existing asset folder, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "clash.md")); !os.IsNotExist(err) {
+		t.Errorf("deck left behind after a failed create: %v", err)
+	}
+	if _, err := tools.CreateProjectFromTemplate(gallery, "other", "missing", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+
+	// Saving the deck back turns its asset folder and theme into placeholders
+	saved, err := tools.SaveProjectAsTemplate(gallery, "review.md", "Team", "Reviews")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID != "team-2" || saved.Theme != "default" || len(saved.Assets) != 1 {
+		t.Errorf("unexpected saved template %+v", saved)
+	}
+	data, _ := os.ReadFile(filepath.Join(gallery.Dir, saved.ID, templateDeckFile))
+	if !strings.Contains(string(data), "theme: {{theme}}") || !strings.Contains(string(data), "]({{assetDir}}/img/logo.png)") {
+		t.Errorf("unexpected template markdown:\n%s", data)
+	}
+	if name, err := tools.CreateProjectFromTemplate(gallery, "talks/next", saved.ID, nil); err != nil || name != "talks/next.md" {
+		t.Errorf("creating from the saved template = %q, %v", name, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "talks", "next-assets", "img", "logo.png")); err != nil {
+		t.Errorf("asset of the saved template not copied: %v", err)
+	}
+
+	if err := gallery.Delete(DefaultTemplate); err == nil {
+		t.Errorf("deleted a builtin template")
+	}
+	if err := gallery.Delete(saved.ID); err != nil {
+		t.Fatal(err)
+	}
+	if templates, _ := gallery.List(); len(templates) != 2 {
+		t.Errorf("expected the builtin and team templates, got %v", templates)
+	}
+}
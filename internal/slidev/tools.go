@@ -1,14 +1,18 @@
 package slidev
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 )
 
+// ErrPageOutOfRange is returned for page indexes beyond the deck
+var ErrPageOutOfRange = errors.New("page index out of range")
+
 // Project represents a Slidev project file
 
 type Project struct {
@@ -21,337 +25,267 @@ type Project struct {
 // Tools provides methods to manipulate Slidev projects
 type Tools struct {
 	WorkingDir string
-	mu         sync.Mutex
+	// OnRename, when set, is called after a deck was renamed or moved, e.g.
+	// to restart a preview serving it
+	OnRename func(oldName, newName string)
+	// OnChange, when set, is called after the content of a deck was written,
+	// e.g. to refresh a search index. It is called with the tools locked and
+	// must not call back into them.
+	OnChange     func(filename string)
+	formatOnSave bool // See SetFormatOnSave
+	mu           sync.Mutex
 }
 
 func NewTools(workingDir string) *Tools {
 	return &Tools{WorkingDir: workingDir}
 }
 
-// ListProjects scans the working directory for .md files
-func (t *Tools) ListProjects() ([]Project, error) {
+// Dir returns the workspace directory
+func (t *Tools) Dir() string {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	var projects []Project
-
-	files, err := os.ReadDir(t.WorkingDir)
-	if err != nil {
-		return nil, err
-	}
-
-	for i, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".md") && file.Name() != "README.md" {
-			info, _ := file.Info()
-			projects = append(projects, Project{
-				ID:      fmt.Sprintf("%d", i),
-				Name:    file.Name(),
-				Updated: info.ModTime().Format("2006-01-02 15:04"),
-				Img:     "https://picsum.photos/seed/" + file.Name() + "/400/225", // Deterministic random image
-			})
-		}
-	}
-	return projects, nil
+	return t.WorkingDir
 }
 
-// CreateProject creates a new .md file
-func (t *Tools) CreateProject(filename string) error {
+// Path resolves a file name in the workspace, rejecting names that would
+// escape it. See ResolvePath.
+func (t *Tools) Path(filename string) (string, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	if !strings.HasSuffix(filename, ".md") {
-		filename += ".md"
-	}
-	// Use CreateDeck logic but for specific filename
-	title := strings.TrimSuffix(filename, ".md")
-	theme := "seriph"
-
-	path := filepath.Join(t.WorkingDir, filename)
-	content := fmt.Sprintf(`---
-theme: %s
-background: https://picsum.photos/id/10/1920/1080
-class: text-center
-highlighter: shiki
-lineNumbers: true
----
-
-# %s
-
-Welcome to Slidev
-
----
-layout: default
----
-
-# Page 2
+	return t.resolve(filename)
+}
 
-Content
-`, theme, title)
+func (t *Tools) resolve(filename string) (string, error) {
+	return ResolvePath(t.WorkingDir, filename)
+}
 
-	return os.WriteFile(path, []byte(content), 0644)
+// writeDeck writes the content of a deck and tells OnChange
+func (t *Tools) writeDeck(filename, path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	if t.OnChange != nil {
+		t.OnChange(filename)
+	}
+	return nil
 }
 
-// CreateDeck initializes a new deck (Legacy/Default support)
-func (t *Tools) CreateDeck(title string, theme string) error {
+// SetWorkingDir switches the tools to another workspace directory
+func (t *Tools) SetWorkingDir(dir string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	// If title doesn't end in .md, we assume it's just the title
-	// But CreateProject expects a filename.
-	// For legacy CreateDeck, we likely want "slides.md" if it's the main deck.
-	// However, the test passes "Test Title" as title, but we want to create "slides.md" in that temp dir?
-	// The implementation of CreateDeck in my memory was calling CreateProject("slides.md").
-	// But `CreateProject` sets the title inside the markdown.
-
-	filename := "slides.md"
-	path := filepath.Join(t.WorkingDir, filename)
-
-	content := fmt.Sprintf(`---
-theme: %s
-background: https://picsum.photos/id/10/1920/1080
-class: text-center
-highlighter: shiki
-lineNumbers: true
----
-
-# %s
+	t.WorkingDir = dir
+}
 
-Welcome to Slidev
+// skippedDirs are folders of the workspace that never hold decks
+var skippedDirs = map[string]bool{"node_modules": true, "dist": true}
 
----
-layout: default
----
+// maxFolderDepth is how deep decks can be in folders of the workspace. It
+// keeps listing cheap when the workspace is a home or root directory.
+const maxFolderDepth = 1
 
-# Page 2
+// ListProjects scans the working directory and its folders, up to
+// maxFolderDepth, for .md files. Names of decks in folders are slash
+// separated, e.g. "talks/intro.md".
+func (t *Tools) ListProjects() ([]Project, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var projects []Project
 
-Content
-`, theme, title)
+	if _, err := os.Stat(t.WorkingDir); err != nil {
+		return nil, err
+	}
+	err := filepath.WalkDir(t.WorkingDir, func(path string, file fs.DirEntry, err error) error {
+		if err != nil {
+			if path == t.WorkingDir {
+				return err
+			}
+			return nil // Unreadable folders are not fatal
+		}
+		if file.IsDir() {
+			if path == t.WorkingDir {
+				return nil
+			}
+			if strings.HasPrefix(file.Name(), ".") || skippedDirs[file.Name()] {
+				return filepath.SkipDir
+			}
+			if rel, err := filepath.Rel(t.WorkingDir, path); err != nil || strings.Count(filepath.ToSlash(rel), "/") >= maxFolderDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !file.Type().IsRegular() || !strings.HasSuffix(file.Name(), ".md") || file.Name() == "README.md" || strings.HasPrefix(file.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(t.WorkingDir, path)
+		if err != nil {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+		info, err := file.Info()
+		if err != nil {
+			return nil
+		}
+		projects = append(projects, Project{
+			ID:      fmt.Sprintf("%d", len(projects)),
+			Name:    name,
+			Updated: info.ModTime().Format("2006-01-02 15:04"),
+			Img:     "https://picsum.photos/seed/" + name + "/400/225", // Deterministic random image
+		})
+		return nil
+	})
+	return projects, err
+}
 
-	return os.WriteFile(path, []byte(content), 0644)
+// CreateProject creates a new .md file from the default template. It fails
+// with ErrProjectExists rather than replace an existing deck.
+func (t *Tools) CreateProject(filename string) error {
+	_, err := t.CreateProjectWithOptions(filename, CreateOptions{})
+	return err
 }
 
-// SaveSlides overwrites a specific file
-func (t *Tools) SaveSlides(filename string, content string) error {
+// CreateDeck initializes slides.md, the workspace's main deck, from the
+// default template
+func (t *Tools) CreateDeck(title string, theme string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	path := filepath.Join(t.WorkingDir, filename)
-	return os.WriteFile(path, []byte(content), 0644)
+	path, err := t.resolve("slides.md")
+	if err != nil {
+		return err
+	}
+	return t.writeDeck("slides.md", path, defaultDeck(title, theme))
 }
 
-// UpdatePage updates a specific page content
-func (t *Tools) UpdatePage(filename string, pageIndex int, markdown string) error {
+// SaveSlides overwrites a specific file, formatting the content first when
+// format on save is on
+func (t *Tools) SaveSlides(filename string, content string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	if filename == "" {
-		filename = "slides.md"
-	}
-	path := filepath.Join(t.WorkingDir, filename)
-	data, err := os.ReadFile(path)
+	path, err := t.resolve(filename)
 	if err != nil {
 		return err
 	}
-
-	content := string(data)
-	// Split by separator ---
-	// Need to be careful about frontmatter. The first --- block is frontmatter.
-	// Slidev splits by `---` (surrounded by newlines mostly).
-
-	// Normalize newlines
-	content = strings.ReplaceAll(content, "\r\n", "\n")
-
-	// Regular expression to match --- only at the start of a line
-	parts := regexp.MustCompile(`(?m)^---$`).Split(content, -1)
-
-	// In Slidev, a slide is defined as:
-	// --- (delimiter)
-	// slide content
-	// --- (delimiter)
-
-	// If the file starts with ---, parts[0] is empty.
-	// parts[1] is the frontmatter.
-	// parts[2] is slide 1 content.
-	// parts[3] is slide 2 content.
-
-	// If the AI says update pageIndex 0 (Slide 1), it maps to parts[2].
-	targetIndex := pageIndex + 2
-	if !strings.HasPrefix(content, "---") {
-		// If no frontmatter (rare), logic changes. Assuming standard Slidev.
-		return fmt.Errorf("invalid slidev format: missing frontmatter")
+	if t.formatOnSave {
+		content = FormatMarkdown(content)
 	}
+	return t.writeDeck(filename, path, content)
+}
 
-	if targetIndex >= len(parts) {
-		return fmt.Errorf("page index out of range")
-	}
-
-	// Simply replace the slide content
-	// Note: Each part is the content BETWEEN two --- delimiters
-	// For a slide with layout, it looks like:
-	// \nlayout: cover\n\n# Title\n\nContent\n
-
-	parts[targetIndex] = "\n" + markdown + "\n"
-
-	newContent := strings.Join(parts, "---")
-	return os.WriteFile(path, []byte(newContent), 0644)
+// UpdatePage replaces the content of a page, keeping its frontmatter. Pages
+// are the slides of ParseDeck: a slide with its own frontmatter is one page.
+func (t *Tools) UpdatePage(filename string, pageIndex int, markdown string) error {
+	return t.editDeck(filename, func(deck *Deck) error {
+		return setPageContent(deck, pageIndex, markdown)
+	})
 }
 
-// InsertPage inserts a new page after a specific index
-func (t *Tools) InsertPage(filename string, afterIndex int, layout string) error {
+// editDeck applies edit to a deck and writes it back
+func (t *Tools) editDeck(filename string, edit func(deck *Deck) error) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	if filename == "" {
 		filename = "slides.md"
 	}
-	path := filepath.Join(t.WorkingDir, filename)
+	path, err := t.resolve(filename)
+	if err != nil {
+		return err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-
-	content := string(data)
-	content = strings.ReplaceAll(content, "\r\n", "\n")
-	parts := regexp.MustCompile(`(?m)^---$`).Split(content, -1)
-
-	targetIndex := afterIndex + 2
-	if targetIndex >= len(parts) {
-		targetIndex = len(parts)
-	} else {
-		targetIndex++ // Insert AFTER
+	deck := ParseDeck(string(data))
+	if err := edit(deck); err != nil {
+		return err
 	}
+	return t.writeDeck(filename, path, deck.String())
+}
 
-	// It's easier to just append text if it's the end, but let's try to splice.
-	// Actually, `parts` does not include the separator.
-
-	// Let's re-read strategy.
-	// We want to insert `---` and content.
-
-	// Simplest: Find the Nth occurrence of `---` and insert after it.
-	// Occurrences: 1st (start), 2nd (end of frontmatter), 3rd (end of slide 1)...
-	// afterIndex 0 (Slide 1) -> Insert after 3rd `---`.
-
-	matches := regexp.MustCompile(`(?m)^---$`).FindAllStringIndex(content, -1)
-
-	// matches[0] is usually index 0.
-	// matches[1] is end of frontmatter.
-	// Slide 1 ends at matches[2] (if exists) or EOF.
-
-	// We want to insert after Slide `afterIndex`.
-	// Slide 1 is between matches[1] and matches[2].
-	// Slide N is between matches[N] and matches[N+1].
-
-	// So we want to insert after Slide `afterIndex` (1-based in UI? 0-based?).
-	// Assuming 0-based index for slides (excluding frontmatter).
-
-	// If we want to insert after Slide 0.
-	// We look for end of Slide 0. This is usually the next `---`.
-	// So match index is `afterIndex + 2`. (0 -> 2).
-
-	// If `afterIndex + 2` exists in matches, we insert before that match? No.
-	// We want to insert AFTER the content of that slide.
-	// Which is BEFORE the delimiter that starts the NEXT slide.
-
-	// Wait.
-	// --- (0)
-	// frontmatter
-	// --- (1)
-	// Slide 0
-	// --- (2)
-	// Slide 1
-
-	// Insert after Slide 0 -> Insert at position of match (2).
-	// The new content will be `\n---\nNewContent\n` and then the existing `---` follows?
-	// Or `\n\nNewContent\n\n---` ?
-
-	// If I insert at match (2):
-	// Slide 0 content
-	// <INSERT HERE>
-	// ---
-	// Slide 1 content
-
-	// Inserted: `\n---\nlayout: ...\n# New\n`
-
-	// Result:
-	// Slide 0 content
-	// ---
-	// layout: ...
-	// # New
-	// ---
-	// Slide 1 content
-
-	// Correct.
-
-	insertPosIndex := afterIndex + 2
-
-	// Build the new slide content
-	// Slidev format: ---\nlayout: xxx\n---\ncontent OR just ---\ncontent
-	var insertion string
-	if layout != "" && layout != "default" {
-		// With layout frontmatter: the closing --- is part of the frontmatter
-		insertion = fmt.Sprintf("\n---\nlayout: %s\n---\n\n# New Slide\n", layout)
-	} else {
-		// Simple slide without frontmatter
-		insertion = "\n---\n\n# New Slide\n"
+// setPageContent replaces the content of slide pageIndex of deck with
+// markdown, keeping the blank lines around it that the deck's layout needs
+func setPageContent(deck *Deck, pageIndex int, markdown string) error {
+	if pageIndex < 0 || pageIndex >= len(deck.Slides) {
+		return fmt.Errorf("%w: %d", ErrPageOutOfRange, pageIndex)
 	}
+	s := &deck.Slides[pageIndex]
+	content := strings.Trim(markdown, "\n") + "\n"
+	if pageIndex > 0 || s.HasFrontmatter {
+		content = "\n" + content
+	}
+	if pageIndex < len(deck.Slides)-1 {
+		content += "\n"
+	}
+	s.Content = content
+	return nil
+}
 
-	if insertPosIndex < len(matches) {
-		pos := matches[insertPosIndex][0] // Start of `---`
+// InsertPage inserts a new page after page afterIndex, counting pages as
+// UpdatePage does; -1 inserts it first. A layout other than "default" goes
+// into the new page's frontmatter.
+func (t *Tools) InsertPage(filename string, afterIndex int, layout string) error {
+	return t.editDeck(filename, func(deck *Deck) error {
+		insertPage(deck, afterIndex, layout)
+		return nil
+	})
+}
 
-		// Avoid double newlines
-		prefix := content[:pos]
-		prefix = strings.TrimRight(prefix, "\n") + "\n"
+// insertPage inserts a new page into deck, see InsertPage
+func insertPage(deck *Deck, afterIndex int, layout string) {
+	deck.trailingNewline = true
+	pos := min(max(afterIndex+1, 0), len(deck.Slides))
 
-		newContent := prefix + insertion + content[pos:]
-		return os.WriteFile(path, []byte(newContent), 0644)
-	} else {
-		// Append to end - ensure content ends with newline
-		trimmedContent := strings.TrimRight(content, "\n") + "\n"
-		newContent := trimmedContent + insertion
-		return os.WriteFile(path, []byte(newContent), 0644)
+	page := Slide{Content: "\n# New Slide\n"}
+	if pos == 0 && len(deck.Slides) > 0 && deck.Slides[0].HasFrontmatter {
+		// The headmatter configures the deck and stays at its top
+		page.Frontmatter, page.HasFrontmatter = deck.Slides[0].Frontmatter, true
+		deck.Slides[0].Frontmatter, deck.Slides[0].HasFrontmatter = "", false
 	}
-}
-
-// ApplyGlobalTheme changes the theme in frontmatter
-func (t *Tools) ApplyGlobalTheme(filename string, themeName string) error {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	if filename == "" {
-		filename = "slides.md"
+	if layout != "" && layout != "default" {
+		page.Frontmatter, page.HasFrontmatter = setFrontmatterField(page.Frontmatter, "layout", layout), true
 	}
-	path := filepath.Join(t.WorkingDir, filename)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return err
+	if pos == 0 && !page.HasFrontmatter {
+		page.Content = "# New Slide\n"
 	}
-
-	content := string(data)
-	// Replace `theme: ...` in frontmatter
-	// Frontmatter is between first two `---`
-
-	re := regexp.MustCompile(`(?s)^---\n(.*?)\n---`)
-	match := re.FindStringSubmatch(content)
-	if len(match) < 2 {
-		return fmt.Errorf("frontmatter not found")
+	if pos > 0 {
+		prev := &deck.Slides[pos-1]
+		prev.Content = strings.TrimRight(prev.Content, "\n") + "\n\n"
 	}
+	if pos < len(deck.Slides) {
+		page.Content += "\n"
+		if next := &deck.Slides[pos]; pos == 0 && !strings.HasPrefix(next.Content, "\n") {
+			next.Content = "\n" + next.Content
+		}
+	}
+	deck.Slides = append(deck.Slides[:pos], append([]Slide{page}, deck.Slides[pos:]...)...)
+}
 
-	frontmatter := match[1]
-	themeRe := regexp.MustCompile(`theme:\s*([^\s]+)`)
-
-	var newFrontmatter string
-	if themeRe.MatchString(frontmatter) {
-		newFrontmatter = themeRe.ReplaceAllString(frontmatter, "theme: "+themeName)
-	} else {
-		newFrontmatter = frontmatter + "\ntheme: " + themeName
+// setFrontmatterField sets a top-level key of a frontmatter block
+func setFrontmatterField(frontmatter, key, value string) string {
+	lines := splitLines(frontmatter)
+	for i, line := range lines {
+		if k, _, _ := strings.Cut(line, ":"); frontmatterKey.MatchString(line) && strings.TrimSpace(k) == key {
+			lines[i] = key + ": " + value
+			return joinLines(lines)
+		}
 	}
+	return joinLines(append(lines, key+": "+value))
+}
 
-	newContent := strings.Replace(content, frontmatter, newFrontmatter, 1)
-	return os.WriteFile(path, []byte(newContent), 0644)
+// ApplyGlobalTheme changes the theme in the headmatter
+func (t *Tools) ApplyGlobalTheme(filename string, themeName string) error {
+	return t.editDeck(filename, func(deck *Deck) error {
+		return setTheme(deck, themeName)
+	})
 }
 
-// DeleteProject deletes a project file
-func (t *Tools) DeleteProject(filename string) error {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	if filename == "" {
-		return fmt.Errorf("filename is required")
+// setTheme sets the theme key of the deck's headmatter
+func setTheme(deck *Deck, themeName string) error {
+	if len(deck.Slides) == 0 || !deck.Slides[0].HasFrontmatter {
+		return fmt.Errorf("frontmatter not found")
 	}
-	path := filepath.Join(t.WorkingDir, filename)
-	return os.Remove(path)
+	head := &deck.Slides[0]
+	head.Frontmatter = setFrontmatterField(head.Frontmatter, "theme", themeName)
+	return nil
 }
 
 func (t *Tools) ReadSlides(filename string) (string, error) {
@@ -360,7 +294,10 @@ func (t *Tools) ReadSlides(filename string) (string, error) {
 	if filename == "" {
 		filename = "slides.md"
 	}
-	path := filepath.Join(t.WorkingDir, filename)
+	path, err := t.resolve(filename)
+	if err != nil {
+		return "", err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "", err
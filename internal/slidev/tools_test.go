@@ -75,3 +75,25 @@ func TestTools(t *testing.T) {
 		t.Errorf("Expected 'theme: new-theme' in content, got: %s", content)
 	}
 }
+
+func TestInsertPageWithFrontmatter(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	base := "---\ntheme: default\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Two\n"
+	if err := tools.SaveSlides("slides.md", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.InsertPage("slides.md", 1, "two-cols"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.UpdatePage("slides.md", 2, "# Three"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.InsertPage("slides.md", -1, "default"); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := tools.ReadSlides("slides.md")
+	want := "---\ntheme: default\n---\n\n# New Slide\n\n---\n\n# One\n\n---\nlayout: center\n---\n\n# Two\n\n---\nlayout: two-cols\n---\n\n# Three\n"
+	if content != want {
+		t.Errorf("got\n%q\nwant\n%q", content, want)
+	}
+}
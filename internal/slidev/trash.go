@@ -0,0 +1,212 @@
+package slidev
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// ErrNotInTrash is returned for trash IDs that do not exist
+var ErrNotInTrash = errors.New("not in trash")
+
+// TrashEntry describes a deleted deck that can still be restored
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"` // File name of the deck before it was deleted
+	DeletedAt time.Time `json:"deletedAt"`
+	Size      int64     `json:"size"`
+}
+
+// Each trashed deck is a directory in .studio/trash holding the entry, the
+// deck file and the deck's sidecar data
+const (
+	trashEntryFile = "entry.json"
+	trashDeckFile  = "deck.md"
+	trashDataDir   = "data"
+)
+
+// Trash entries and change sets have IDs made of a timestamp, which sorts
+// them, and a random suffix
+var entryIDPattern = regexp.MustCompile(`^[0-9]+-[0-9a-f]{8}$`)
+
+func newEntryID(now time.Time) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%s", now.UnixNano(), hex.EncodeToString(b)), nil
+}
+
+// DeleteProject moves a project file and its sidecar data to the trash
+func (t *Tools) DeleteProject(filename string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if filename == "" {
+		return fmt.Errorf("filename is required")
+	}
+	path, err := t.resolve(filename)
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a deck file", filename)
+	}
+
+	now := time.Now()
+	id, err := newEntryID(now)
+	if err != nil {
+		return err
+	}
+	dir := t.sidecarPath("trash", id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	entry := TrashEntry{ID: id, Name: filename, DeletedAt: now.UTC(), Size: info.Size()}
+	if err := writeTrashEntry(dir, entry); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	// Renaming keeps the file's modification time and permissions
+	if err := os.Rename(path, filepath.Join(dir, trashDeckFile)); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("failed to move %s to the trash: %w", filename, err)
+	}
+	if err := os.Rename(t.deckDataDir(filename), filepath.Join(dir, trashDataDir)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move the data of %s to the trash: %w", filename, err)
+	}
+	return nil
+}
+
+// ListTrash returns the decks in the trash, most recently deleted first
+func (t *Tools) ListTrash() ([]TrashEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.listTrash()
+}
+
+func (t *Tools) listTrash() ([]TrashEntry, error) {
+	dirs, err := os.ReadDir(t.sidecarPath("trash"))
+	if os.IsNotExist(err) {
+		return []TrashEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := []TrashEntry{}
+	for _, d := range dirs {
+		if !d.IsDir() || !entryIDPattern.MatchString(d.Name()) {
+			continue
+		}
+		entry, err := readTrashEntry(t.sidecarPath("trash", d.Name()))
+		if err != nil {
+			continue // Half-written or damaged; EmptyTrash still removes it
+		}
+		entry.ID = d.Name()
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.After(entries[j].DeletedAt) })
+	return entries, nil
+}
+
+// RestoreProject moves a deck out of the trash and returns its file name. A
+// deck that has since been replaced is restored next to it under a new name.
+func (t *Tools) RestoreProject(id string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !entryIDPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: %q", ErrNotInTrash, id)
+	}
+	dir := t.sidecarPath("trash", id)
+	entry, err := readTrashEntry(dir)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %q", ErrNotInTrash, id)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	name, target, err := t.freeName(entry.Name, "restored")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(filepath.Join(dir, trashDeckFile), target); err != nil {
+		return "", fmt.Errorf("failed to restore %s: %w", entry.Name, err)
+	}
+	data := filepath.Join(dir, trashDataDir)
+	if _, err := os.Stat(data); err == nil {
+		dataDir := t.deckDataDir(name)
+		// Leftovers of an earlier deck by that name would mix with the history
+		if err := os.RemoveAll(dataDir); err != nil {
+			return name, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dataDir), 0755); err != nil {
+			return name, err
+		}
+		if err := os.Rename(data, dataDir); err != nil {
+			return name, fmt.Errorf("failed to restore the data of %s: %w", name, err)
+		}
+	}
+	return name, os.RemoveAll(dir)
+}
+
+// EmptyTrash permanently deletes everything in the trash
+func (t *Tools) EmptyTrash() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return os.RemoveAll(t.sidecarPath("trash"))
+}
+
+// PurgeTrash permanently deletes decks that have been in the trash for longer
+// than retention and returns how many were removed
+func (t *Tools) PurgeTrash(retention time.Duration) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries, err := t.listTrash()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-retention)
+	purged := 0
+	for _, e := range entries {
+		if e.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(t.sidecarPath("trash", e.ID)); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func readTrashEntry(dir string) (TrashEntry, error) {
+	var entry TrashEntry
+	data, err := os.ReadFile(filepath.Join(dir, trashEntryFile))
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+func writeTrashEntry(dir string, entry TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, trashEntryFile), data, 0644)
+}
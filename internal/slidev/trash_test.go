@@ -0,0 +1,120 @@
+package slidev
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrash(t *testing.T) {
+	root := t.TempDir()
+	tools := NewTools(root)
+	if err := tools.CreateProject("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+	deck := filepath.Join(root, "talk.md")
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(deck, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	// Sidecar data, e.g. history, travels with the deck
+	history := filepath.Join(tools.deckDataDir("talk.md"), "history.json")
+	if err := os.MkdirAll(filepath.Dir(history), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(history, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tools.DeleteProject("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(deck); !os.IsNotExist(err) {
+		t.Fatalf("deck still in the workspace")
+	}
+	if _, err := os.Stat(history); !os.IsNotExist(err) {
+		t.Fatalf("deck data still in the workspace")
+	}
+	if projects, _ := tools.ListProjects(); len(projects) != 0 {
+		t.Errorf("trashed deck is still listed: %v", projects)
+	}
+
+	entries, err := tools.ListTrash()
+	if err != nil || len(entries) != 1 || entries[0].Name != "talk.md" || entries[0].Size == 0 {
+		t.Fatalf("ListTrash = %v, %v", entries, err)
+	}
+
+	// A new deck took the name in the meantime
+	if err := tools.CreateProject("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+	name, err := tools.RestoreProject(entries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "talk (restored).md" {
+		t.Errorf("restored as %q", name)
+	}
+	info, err := os.Stat(filepath.Join(root, name))
+	if err != nil || !info.ModTime().Equal(modTime) {
+		t.Errorf("restored deck lost its modification time: %v, %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(tools.deckDataDir(name), "history.json")); err != nil {
+		t.Errorf("deck data not restored: %v", err)
+	}
+	if entries, _ := tools.ListTrash(); len(entries) != 0 {
+		t.Errorf("restored deck still in the trash: %v", entries)
+	}
+	if _, err := tools.RestoreProject(entries[0].ID); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("expected ErrNotInTrash restoring twice, got %v", err)
+	}
+	if _, err := tools.RestoreProject("../../etc"); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("expected ErrNotInTrash for a bogus ID, got %v", err)
+	}
+
+	if err := tools.DeleteProject("talk.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.EmptyTrash(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := tools.ListTrash(); len(entries) != 0 {
+		t.Errorf("trash not emptied: %v", entries)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	tools := NewTools(t.TempDir())
+	for _, name := range []string{"old.md", "new.md"} {
+		if err := tools.CreateProject(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := tools.DeleteProject(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Backdate one entry past the retention
+	entries, _ := tools.ListTrash()
+	for _, e := range entries {
+		if e.Name == "old.md" {
+			e.DeletedAt = time.Now().Add(-40 * 24 * time.Hour)
+			data, _ := json.Marshal(e)
+			if err := os.WriteFile(filepath.Join(tools.sidecarPath("trash", e.ID), trashEntryFile), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	purged, err := tools.PurgeTrash(30 * 24 * time.Hour)
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeTrash = %d, %v", purged, err)
+	}
+	entries, _ = tools.ListTrash()
+	if len(entries) != 1 || entries[0].Name != "new.md" {
+		t.Errorf("unexpected trash after purge: %v", entries)
+	}
+}
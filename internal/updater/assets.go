@@ -0,0 +1,182 @@
+package updater
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+)
+
+// Release assets are matched by name. Names are split into tokens on "-", "_"
+// and "." and are expected to carry the OS, the architecture and, for
+// Windows executables, whether it is an installer:
+//
+//	slidev-studio-ai-amd64-installer.exe
+//	slidev-studio-ai-macos-universal.zip
+//	slidev-studio-ai-linux-amd64.tar.gz
+//
+// Assets without an architecture are accepted for any architecture but are
+// ranked below exact and universal builds, except on macOS: Macs run both
+// arm64 and amd64, so a build that does not say which is never picked.
+
+// ErrNoCompatibleAsset is returned when a release has no download that can be
+// installed on this platform
+var ErrNoCompatibleAsset = errors.New("release has no compatible download for this platform")
+
+// AssetKind is the packaging of a release asset
+type AssetKind string
+
+const (
+	KindInstaller AssetKind = "installer" // Windows NSIS installer
+	KindPortable  AssetKind = "portable"  // Bare Windows executable
+	KindAppZip    AssetKind = "app-zip"   // Zipped macOS .app bundle
+	KindDMG       AssetKind = "dmg"       // macOS disk image
+	KindTarball   AssetKind = "tarball"   // .tar.gz containing the executable
+)
+
+// kindPreference lists the kinds Install can handle per OS, most preferred
+// first. Kinds not listed are never selected.
+var kindPreference = map[string][]AssetKind{
+	"windows": {KindInstaller},
+	"darwin":  {KindAppZip},
+	"linux":   {KindTarball},
+}
+
+// Platform identifies an OS and architecture with GOOS/GOARCH values
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// CurrentPlatform returns the platform the app is running on
+func CurrentPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+var osAliases = map[string]string{
+	"windows": "windows", "win": "windows", "win32": "windows", "win64": "windows",
+	"darwin": "darwin", "macos": "darwin", "mac": "darwin", "osx": "darwin",
+	"linux": "linux",
+}
+
+const archUniversal = "universal"
+
+var archAliases = map[string]string{
+	"amd64": "amd64", "x64": "amd64", "x86_64": "amd64", "win64": "amd64",
+	"arm64": "arm64", "aarch64": "arm64",
+	"386": "386", "i386": "386", "i686": "386", "x86": "386", "win32": "386",
+	"universal": archUniversal,
+}
+
+// AssetInfo is what an asset name says about its platform and packaging.
+// OS and Arch are empty when the name does not specify them.
+type AssetInfo struct {
+	OS   string
+	Arch string
+	Kind AssetKind
+}
+
+// ParseAssetName reads the platform and packaging from an asset name. ok is
+// false for names that are not app downloads, such as checksum files.
+func ParseAssetName(name string) (info AssetInfo, ok bool) {
+	lower := strings.ToLower(name)
+	var ext string
+	for _, e := range []string{".tar.gz", ".tgz", ".zip", ".exe", ".dmg"} {
+		if strings.HasSuffix(lower, e) {
+			ext = e
+			break
+		}
+	}
+	if ext == "" {
+		return AssetInfo{}, false
+	}
+
+	// x86_64 would otherwise be split in two
+	base := strings.ReplaceAll(strings.TrimSuffix(lower, ext), "x86_64", "amd64")
+	tokens := strings.FieldsFunc(base, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+
+	installer := false
+	for _, tok := range tokens {
+		if goos, ok := osAliases[tok]; ok && info.OS == "" {
+			info.OS = goos
+		}
+		if arch, ok := archAliases[tok]; ok && info.Arch == "" {
+			info.Arch = arch
+		}
+		if tok == "installer" || tok == "setup" {
+			installer = true
+		}
+	}
+
+	switch ext {
+	case ".exe":
+		info.OS = "windows"
+		info.Kind = KindPortable
+		if installer {
+			info.Kind = KindInstaller
+		}
+	case ".dmg":
+		info.OS = "darwin"
+		info.Kind = KindDMG
+	case ".zip":
+		if info.OS != "darwin" {
+			return AssetInfo{}, false
+		}
+		info.Kind = KindAppZip
+	default:
+		if info.OS == "" {
+			return AssetInfo{}, false
+		}
+		info.Kind = KindTarball
+	}
+	return info, true
+}
+
+// archRank orders how well an asset's architecture fits; -1 means it does not
+func archRank(assetArch string, p Platform) int {
+	switch {
+	case assetArch == p.Arch:
+		return 0
+	case assetArch == archUniversal && p.OS == "darwin":
+		return 1
+	case assetArch == "" && p.OS != "darwin":
+		return 2
+	default:
+		return -1
+	}
+}
+
+// SelectAsset picks the best download for the platform: the most preferred
+// installable kind first, then the most specific architecture. It returns
+// ErrNoCompatibleAsset when nothing fits.
+func SelectAsset(assets []Asset, p Platform) (*Asset, AssetInfo, error) {
+	kinds := kindPreference[p.OS]
+	best, bestKind, bestArch := -1, 0, 0
+	var bestInfo AssetInfo
+	for i, asset := range assets {
+		info, ok := ParseAssetName(asset.Name)
+		if !ok || info.OS != p.OS {
+			continue
+		}
+		kind := indexOfKind(kinds, info.Kind)
+		arch := archRank(info.Arch, p)
+		if kind < 0 || arch < 0 {
+			continue
+		}
+		if best < 0 || kind < bestKind || (kind == bestKind && arch < bestArch) {
+			best, bestKind, bestArch, bestInfo = i, kind, arch, info
+		}
+	}
+	if best < 0 {
+		return nil, AssetInfo{}, ErrNoCompatibleAsset
+	}
+	return &assets[best], bestInfo, nil
+}
+
+func indexOfKind(kinds []AssetKind, kind AssetKind) int {
+	for i, k := range kinds {
+		if k == kind {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,77 @@
+package updater
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectAsset(t *testing.T) {
+	release := []Asset{
+		{Name: "checksums.txt"},
+		{Name: "checksums.txt.sig"},
+		{Name: "slidev-studio-ai-amd64.exe"},
+		{Name: "slidev-studio-ai-amd64-installer.exe"},
+		{Name: "slidev-studio-ai-arm64-installer.exe"},
+		{Name: "slidev-studio-ai-macos-x86_64.zip"},
+		{Name: "slidev-studio-ai-macos-arm64.zip"},
+		{Name: "slidev-studio-ai-macos-universal.dmg"},
+		{Name: "slidev-studio-ai-linux-amd64.tar.gz"},
+		{Name: "slidev-studio-ai-windows-amd64.zip"},
+	}
+
+	tests := []struct {
+		platform Platform
+		assets   []Asset
+		want     string // Empty when no asset is compatible
+	}{
+		{Platform{"windows", "amd64"}, release, "slidev-studio-ai-amd64-installer.exe"},
+		{Platform{"windows", "arm64"}, release, "slidev-studio-ai-arm64-installer.exe"},
+		{Platform{"windows", "386"}, release, ""},
+		{Platform{"darwin", "arm64"}, release, "slidev-studio-ai-macos-arm64.zip"},
+		{Platform{"darwin", "amd64"}, release, "slidev-studio-ai-macos-x86_64.zip"},
+		{Platform{"linux", "amd64"}, release, "slidev-studio-ai-linux-amd64.tar.gz"},
+		{Platform{"linux", "arm64"}, release, ""},
+		// Universal builds beat unspecified ones, exact builds beat both
+		{Platform{"darwin", "arm64"}, []Asset{{Name: "app-macos.zip"}, {Name: "app-macos-universal.zip"}}, "app-macos-universal.zip"},
+		{Platform{"darwin", "arm64"}, []Asset{{Name: "app-macos-universal.zip"}, {Name: "app-macos-arm64.zip"}}, "app-macos-arm64.zip"},
+		// A macOS build without an architecture may be for either one
+		{Platform{"darwin", "amd64"}, []Asset{{Name: "slidev-studio-ai-macos.zip"}}, ""},
+		{Platform{"darwin", "arm64"}, []Asset{{Name: "slidev-studio-ai-macos.zip"}}, ""},
+		{Platform{"linux", "amd64"}, []Asset{{Name: "app-linux.tar.gz"}}, "app-linux.tar.gz"},
+		// A portable exe or a disk image can't be installed in-app
+		{Platform{"windows", "amd64"}, []Asset{{Name: "app-amd64.exe"}}, ""},
+		{Platform{"darwin", "arm64"}, []Asset{{Name: "app-macos-arm64.dmg"}}, ""},
+	}
+	for _, tt := range tests {
+		asset, _, err := SelectAsset(tt.assets, tt.platform)
+		if tt.want == "" {
+			if !errors.Is(err, ErrNoCompatibleAsset) {
+				t.Errorf("%v: expected ErrNoCompatibleAsset, got %v, %v", tt.platform, asset, err)
+			}
+			continue
+		}
+		if err != nil || asset.Name != tt.want {
+			t.Errorf("%v: got %v, %v, want %s", tt.platform, asset, err, tt.want)
+		}
+	}
+}
+
+func TestParseAssetName(t *testing.T) {
+	tests := []struct {
+		name string
+		want AssetInfo
+		ok   bool
+	}{
+		{"Slidev-Studio-AI-Win64-Setup.exe", AssetInfo{"windows", "amd64", KindInstaller}, true},
+		{"app_linux_aarch64.tgz", AssetInfo{"linux", "arm64", KindTarball}, true},
+		{"app-1.2.0-osx-x64.zip", AssetInfo{"darwin", "amd64", KindAppZip}, true},
+		{"app.tar.gz", AssetInfo{}, false},
+		{"checksums.txt", AssetInfo{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAssetName(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseAssetName(%q) = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
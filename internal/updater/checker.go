@@ -0,0 +1,158 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCheckInterval is how often the background check looks for updates
+const DefaultCheckInterval = 24 * time.Hour
+
+// CheckState is persisted between runs so checks survive restarts without
+// refetching unchanged release lists
+type CheckState struct {
+	URL            string    `json:"url"` // Releases endpoint the cache belongs to
+	LastCheck      time.Time `json:"lastCheck"`
+	ETag           string    `json:"etag,omitempty"`
+	Releases       []Release `json:"releases,omitempty"`
+	RateLimitReset time.Time `json:"rateLimitReset,omitempty"`
+}
+
+// Checker checks for updates with ETag caching and rate-limit backoff, and
+// can run the check periodically in the background
+type Checker struct {
+	CurrentVersion string
+	StatePath      string
+	Options        func() Options // Called before every check so config changes apply
+	Interval       time.Duration  // DefaultCheckInterval when zero
+
+	// OnAvailable is called by Run when a check finds an update that was not
+	// skipped
+	OnAvailable func(*UpdateInfo)
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func (c *Checker) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *Checker) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return DefaultCheckInterval
+}
+
+// State returns the persisted check state
+func (c *Checker) State() CheckState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadState()
+}
+
+func (c *Checker) loadState() CheckState {
+	var state CheckState
+	if data, err := os.ReadFile(c.StatePath); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func (c *Checker) saveState(state CheckState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.StatePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.StatePath, data, 0644)
+}
+
+// Check looks for an update. The request is conditional on the cached ETag,
+// and while the API rate limit is exhausted the cached releases are used
+// instead; without a cache that returns a *RateLimitError.
+func (c *Checker) Check(ctx context.Context) (*UpdateInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	opts := c.Options().withDefaults()
+	url, err := releasesURL(opts)
+	if err != nil {
+		return nil, err
+	}
+	state := c.loadState()
+	if state.URL != url {
+		state = CheckState{URL: url}
+	}
+
+	if now := c.clock(); now.Before(state.RateLimitReset) {
+		if state.Releases == nil {
+			return nil, &RateLimitError{Reset: state.RateLimitReset}
+		}
+		return selectUpdate(c.CurrentVersion, state.Releases, opts)
+	}
+
+	res, err := fetchReleases(ctx, opts, state.ETag)
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		state.RateLimitReset = rateErr.Reset
+		_ = c.saveState(state)
+		if state.Releases == nil {
+			return nil, err
+		}
+		return selectUpdate(c.CurrentVersion, state.Releases, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.NotModified {
+		state.Releases = res.Releases
+		state.ETag = res.ETag
+	}
+	state.LastCheck = c.clock()
+	state.RateLimitReset = res.RateLimitReset
+	if err := c.saveState(state); err != nil {
+		return nil, err
+	}
+	return selectUpdate(c.CurrentVersion, state.Releases, opts)
+}
+
+// due reports whether the last successful check is older than the interval
+func (c *Checker) due() bool {
+	return c.clock().Sub(c.State().LastCheck) >= c.interval()
+}
+
+// Run checks for updates whenever a check is due until ctx is cancelled.
+// Errors are not reported; the check is simply retried later.
+func (c *Checker) Run(ctx context.Context) {
+	// Wake up more often than the interval so a check missed while the
+	// machine slept or was offline happens soon after
+	ticker := time.NewTicker(min(c.interval(), time.Hour))
+	defer ticker.Stop()
+
+	for {
+		if c.due() {
+			info, err := c.Check(ctx)
+			if err == nil && info.Available && !info.Skipped && c.OnAvailable != nil {
+				c.OnAvailable(info)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
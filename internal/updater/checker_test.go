@@ -0,0 +1,130 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	releases := []Release{{TagName: "v1.1.0", Body: "## Fixes\n- Faster **export**"}}
+	var requests, notModified int
+	rateLimited := false
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if rateLimited {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer srv.Close()
+
+	skip := ""
+	now := time.Now()
+	c := &Checker{
+		CurrentVersion: "v1.0.0",
+		StatePath:      filepath.Join(t.TempDir(), "check.json"),
+		Options: func() Options {
+			return Options{Repository: "acme/decks", APIBaseURL: srv.URL, SkipVersion: skip}
+		},
+		now: func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	info, err := c.Check(ctx)
+	if err != nil || !info.Available || info.Version != "v1.1.0" {
+		t.Fatalf("first check = %+v, %v", info, err)
+	}
+	if !strings.Contains(info.NotesHTML, "<h2>Fixes</h2>") || !strings.Contains(info.NotesHTML, "<li>Faster <strong>export</strong></li>") {
+		t.Errorf("unexpected release notes HTML %q", info.NotesHTML)
+	}
+	if c.due() {
+		t.Errorf("check should not be due right after checking")
+	}
+
+	// The second check is conditional and served from the cache
+	info, err = c.Check(ctx)
+	if err != nil || info.Version != "v1.1.0" || notModified != 1 {
+		t.Fatalf("cached check = %+v, %v (304s: %d)", info, err, notModified)
+	}
+
+	// A skipped version is still reported but flagged
+	skip = "1.1.0"
+	if info, _ := c.Check(ctx); !info.Skipped {
+		t.Errorf("expected v1.1.0 to be marked skipped")
+	}
+	skip = ""
+
+	// Once rate limited, no requests are made until the reset
+	rateLimited = true
+	if info, err := c.Check(ctx); err != nil || info.Version != "v1.1.0" {
+		t.Fatalf("rate limited check should fall back to the cache, got %+v, %v", info, err)
+	}
+	if got := c.State().RateLimitReset; !got.Equal(reset) {
+		t.Errorf("rate limit reset = %v, want %v", got, reset)
+	}
+	before := requests
+	if _, err := c.Check(ctx); err != nil || requests != before {
+		t.Errorf("expected no request before the rate limit resets (err %v)", err)
+	}
+	rateLimited = false
+	now = reset.Add(time.Second)
+	if _, err := c.Check(ctx); err != nil || requests != before+1 {
+		t.Errorf("expected a request after the rate limit reset (err %v)", err)
+	}
+	if c.due() {
+		t.Errorf("check should not be due right after checking")
+	}
+	now = now.Add(DefaultCheckInterval)
+	if !c.due() {
+		t.Errorf("check should be due after the interval")
+	}
+}
+
+func TestCheckerRateLimitedWithoutCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := &Checker{
+		CurrentVersion: "v1.0.0",
+		StatePath:      filepath.Join(t.TempDir(), "check.json"),
+		Options:        func() Options { return Options{Repository: "acme/decks", APIBaseURL: srv.URL} },
+	}
+	_, err := c.Check(context.Background())
+	var rateErr *RateLimitError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rateErr) || time.Until(rateErr.Reset) < 50*time.Second {
+		t.Fatalf("expected a RateLimitError about a minute out, got %v", err)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	got := RenderMarkdown("# v1.1 <beta>\n\nSee [docs](https://example.com/a?b=1&c=2) and [x](javascript:void).\n\n1. `a < b`\n2. *new*\n\n```\n<raw>\n```")
+	want := "<h1>v1.1 &lt;beta&gt;</h1>\n" +
+		"<p>See <a href=\"https://example.com/a?b=1&amp;c=2\">docs</a> and x.</p>\n" +
+		"<ol>\n<li><code>a &lt; b</code></li>\n<li><em>new</em></li>\n</ol>\n" +
+		"<pre><code>&lt;raw&gt;\n</code></pre>\n"
+	if got != want {
+		t.Errorf("RenderMarkdown:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,390 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PublicKey is the base64 encoded ed25519 key release checksums are signed
+// with. It is injected at build time:
+//
+//	-ldflags "-X slidev-studio-ai/internal/updater.PublicKey=<base64>"
+var PublicKey = ""
+
+// checksumFileNames are the names a release's checksums asset may have; the
+// signature is published next to it with a ".sig" suffix
+var checksumFileNames = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+var (
+	ErrNoPublicKey       = errors.New("no update signing key configured in this build")
+	ErrBadSignature      = errors.New("checksums signature verification failed")
+	ErrChecksumMismatch  = errors.New("downloaded file does not match the published checksum")
+	ErrNoChecksums       = errors.New("release does not publish checksums and a signature")
+	ErrNothingToDownload = errors.New("no downloadable update")
+)
+
+const pendingManifest = "pending.json"
+
+// Progress reports how much of an update has been downloaded
+type Progress struct {
+	Version    string `json:"version"`
+	Downloaded int64  `json:"downloaded"`
+	Total      int64  `json:"total"` // -1 when the server does not report a size
+}
+
+// StagedUpdate is a verified update waiting to be installed on next launch
+type StagedUpdate struct {
+	Version   string    `json:"version"`
+	AssetName string    `json:"assetName"`
+	Path      string    `json:"path"`
+	SHA256    string    `json:"sha256"`
+	StagedAt  time.Time `json:"stagedAt"`
+	// InstallStarted is set by MarkInstallStarted when an installer that
+	// outlives the app was launched. Finding it set on a later start of an
+	// older version means the install did not complete.
+	InstallStarted time.Time `json:"installStarted,omitempty"`
+}
+
+// Downloader fetches, verifies and stages updates
+type Downloader struct {
+	Dir        string            // Staging directory
+	Client     *http.Client      // A client that times out waiting for response headers when nil
+	PublicKey  ed25519.PublicKey // Decoded from PublicKey when nil
+	OnProgress func(Progress)
+}
+
+func (d *Downloader) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	// No overall timeout: large downloads on slow links are fine as long as
+	// the server responds
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = DefaultTimeout
+	d.Client = &http.Client{Transport: transport}
+	return d.Client
+}
+
+func (d *Downloader) publicKey() (ed25519.PublicKey, error) {
+	if d.PublicKey != nil {
+		return d.PublicKey, nil
+	}
+	if PublicKey == "" {
+		return nil, ErrNoPublicKey
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid update signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Download fetches the update described by info, resuming a previous partial
+// download, verifies it against the signed checksums file and stages it for
+// installation on next launch
+func (d *Downloader) Download(ctx context.Context, info *UpdateInfo) (*StagedUpdate, error) {
+	if info == nil || !info.Available {
+		return nil, ErrNothingToDownload
+	}
+	if info.NoCompatibleAsset || info.DownloadURL == "" {
+		return nil, ErrNoCompatibleAsset
+	}
+	if info.ChecksumsURL == "" || info.SignatureURL == "" {
+		return nil, ErrNoChecksums
+	}
+	key, err := d.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the checksums file before trusting anything it says
+	checksums, err := d.fetchSmall(ctx, info.ChecksumsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checksums: %w", err)
+	}
+	sig, err := d.fetchSmall(ctx, info.SignatureURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signature: %w", err)
+	}
+	if !ed25519.Verify(key, checksums, decodeSignature(sig)) {
+		return nil, ErrBadSignature
+	}
+	want, ok := parseChecksums(checksums)[info.AssetName]
+	if !ok {
+		return nil, fmt.Errorf("checksums file has no entry for %s", info.AssetName)
+	}
+
+	// Asset names do not change between releases, so partial downloads are
+	// kept per version; one left from an older release is never resumed
+	if info.Version == "" || strings.ContainsAny(info.Version, `/\`) || strings.Trim(info.Version, ".") == "" {
+		return nil, fmt.Errorf("invalid update version %q", info.Version)
+	}
+	versionDir := filepath.Join(d.Dir, info.Version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return nil, err
+	}
+	part := filepath.Join(versionDir, info.AssetName+".part")
+	removeStalePartials(d.Dir, part)
+	if err := d.fetchResumable(ctx, info, part); err != nil {
+		return nil, err
+	}
+
+	got, err := fileSHA256(part)
+	if err != nil {
+		return nil, err
+	}
+	if got != want {
+		_ = os.Remove(part)
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, info.AssetName)
+	}
+
+	staged := &StagedUpdate{
+		Version:   info.Version,
+		AssetName: info.AssetName,
+		Path:      filepath.Join(versionDir, info.AssetName),
+		SHA256:    got,
+		StagedAt:  time.Now().UTC(),
+	}
+	if err := os.Rename(part, staged.Path); err != nil {
+		return nil, err
+	}
+	if err := writePending(d.Dir, staged); err != nil {
+		return nil, err
+	}
+	return staged, nil
+}
+
+// removeStalePartials deletes the partial downloads of other versions than
+// the one going to keep
+func removeStalePartials(dir, keep string) {
+	parts, _ := filepath.Glob(filepath.Join(dir, "*", "*.part"))
+	for _, p := range parts {
+		if p != keep {
+			_ = os.Remove(p)
+		}
+	}
+}
+
+func writePending(dir string, staged *StagedUpdate) error {
+	data, err := json.MarshalIndent(staged, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, pendingManifest), data, 0644)
+}
+
+// fetchSmall downloads a small text asset such as a checksums file
+func (d *Downloader) fetchSmall(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// fetchResumable downloads info.DownloadURL into path, continuing from the
+// bytes already present with a Range request
+func (d *Downloader) fetchResumable(ctx context.Context, info *UpdateInfo, path string) error {
+	var offset int64
+	if st, err := os.Stat(path); err == nil {
+		offset = st.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.DownloadURL, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := int64(-1)
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+	case http.StatusOK:
+		// Server ignored the range; start over
+		offset = 0
+		flags |= os.O_TRUNC
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete (or bogus; the checksum decides)
+		return nil
+	default:
+		return fmt.Errorf("failed to download update: %s", resp.Status)
+	}
+
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := &progressWriter{w: f, p: Progress{Version: info.Version, Downloaded: offset, Total: total}, fn: d.OnProgress}
+	w.report()
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download interrupted: %w", err)
+	}
+	return nil
+}
+
+type progressWriter struct {
+	w    io.Writer
+	p    Progress
+	fn   func(Progress)
+	last time.Time
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.p.Downloaded += int64(n)
+	if time.Since(pw.last) > 100*time.Millisecond || pw.p.Downloaded == pw.p.Total {
+		pw.report()
+	}
+	return n, err
+}
+
+func (pw *progressWriter) report() {
+	pw.last = time.Now()
+	if pw.fn != nil {
+		pw.fn(pw.p)
+	}
+}
+
+// Pending returns the staged update in dir, or nil when there is none. The
+// staged file is re-hashed so a tampered or truncated file is never installed.
+func Pending(dir string) (*StagedUpdate, error) {
+	data, err := os.ReadFile(filepath.Join(dir, pendingManifest))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var staged StagedUpdate
+	if err := json.Unmarshal(data, &staged); err != nil {
+		return nil, fmt.Errorf("invalid pending update manifest: %w", err)
+	}
+	got, err := fileSHA256(staged.Path)
+	if err != nil {
+		return nil, err
+	}
+	if got != staged.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, staged.Path)
+	}
+	return &staged, nil
+}
+
+// MarkInstallStarted records that the installer of the staged update in dir
+// was launched. The update stays pending until the new version starts.
+func MarkInstallStarted(dir string) error {
+	staged, err := Pending(dir)
+	if err != nil {
+		return err
+	}
+	if staged == nil {
+		return os.ErrNotExist
+	}
+	staged.InstallStarted = time.Now().UTC()
+	return writePending(dir, staged)
+}
+
+// ClearPending removes the staged update and its manifest
+func ClearPending(dir string) error {
+	staged, _ := Pending(dir)
+	if staged != nil {
+		_ = os.RemoveAll(filepath.Dir(staged.Path))
+	}
+	err := os.Remove(filepath.Join(dir, pendingManifest))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// checksumAssets returns the URLs of the checksums file and its signature
+func checksumAssets(assets []Asset) (checksumsURL, signatureURL string) {
+	byName := make(map[string]string, len(assets))
+	for _, a := range assets {
+		byName[a.Name] = a.BrowserDownloadURL
+	}
+	for _, name := range checksumFileNames {
+		if sums, ok := byName[name]; ok {
+			if sig, ok := byName[name+".sig"]; ok {
+				return sums, sig
+			}
+		}
+	}
+	return "", ""
+}
+
+// parseChecksums reads sha256sum output: "<hex>  <name>" or "<hex> *<name>"
+func parseChecksums(data []byte) map[string]string {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// decodeSignature accepts raw or base64 encoded signatures
+func decodeSignature(sig []byte) []byte {
+	if len(sig) == ed25519.SignatureSize {
+		return sig
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return sig
+	}
+	return decoded
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,156 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// releaseServer serves an update asset, its checksums file and signature
+type releaseServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	ranges []string // Range headers of asset requests
+}
+
+func newReleaseServer(t *testing.T, asset, checksums, sig []byte) *releaseServer {
+	rs := &releaseServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		rs.mu.Lock()
+		rs.ranges = append(rs.ranges, r.Header.Get("Range"))
+		rs.mu.Unlock()
+		http.ServeContent(w, r, "app.tar.gz", time.Time{}, bytes.NewReader(asset))
+	})
+	mux.HandleFunc("/checksums.txt", func(w http.ResponseWriter, r *http.Request) { w.Write(checksums) })
+	mux.HandleFunc("/checksums.txt.sig", func(w http.ResponseWriter, r *http.Request) { w.Write(sig) })
+	rs.Server = httptest.NewServer(mux)
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *releaseServer) info() *UpdateInfo {
+	return &UpdateInfo{
+		Available:    true,
+		Version:      "v1.2.0",
+		AssetName:    "app.tar.gz",
+		DownloadURL:  rs.URL + "/app.tar.gz",
+		ChecksumsURL: rs.URL + "/checksums.txt",
+		SignatureURL: rs.URL + "/checksums.txt.sig",
+	}
+}
+
+func signedChecksums(t *testing.T, key ed25519.PrivateKey, asset []byte) (checksums, sig []byte) {
+	sum := sha256.Sum256(asset)
+	checksums = []byte(fmt.Sprintf("%s  app.tar.gz\n%s  other.zip\n", hex.EncodeToString(sum[:]), hex.EncodeToString(make([]byte, 32))))
+	sig = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, checksums)))
+	return checksums, sig
+}
+
+func TestDownload(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset := bytes.Repeat([]byte("slidev-studio-ai update payload "), 4096)
+	checksums, sig := signedChecksums(t, priv, asset)
+
+	t.Run("resumes and stages", func(t *testing.T) {
+		rs := newReleaseServer(t, asset, checksums, sig)
+		dir := t.TempDir()
+
+		// A previous attempt was interrupted half way, and one of an older
+		// release with the same asset name was too
+		for version, data := range map[string][]byte{"v1.2.0": asset[:len(asset)/2], "v1.1.0": []byte("older release")} {
+			if err := os.MkdirAll(filepath.Join(dir, version), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, version, "app.tar.gz.part"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var last Progress
+		d := &Downloader{Dir: dir, PublicKey: pub, OnProgress: func(p Progress) { last = p }}
+		staged, err := d.Download(context.Background(), rs.info())
+		if err != nil {
+			t.Fatalf("Download: %v", err)
+		}
+
+		want := fmt.Sprintf("bytes=%d-", len(asset)/2)
+		if len(rs.ranges) != 1 || rs.ranges[0] != want {
+			t.Errorf("expected one request with Range %q, got %q", want, rs.ranges)
+		}
+		if last.Downloaded != int64(len(asset)) || last.Total != int64(len(asset)) {
+			t.Errorf("unexpected final progress %+v", last)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "v1.1.0", "app.tar.gz.part")); !os.IsNotExist(err) {
+			t.Errorf("partial download of an older release should be removed")
+		}
+		data, err := os.ReadFile(staged.Path)
+		if err != nil || !bytes.Equal(data, asset) {
+			t.Fatalf("staged file does not match the asset (err %v)", err)
+		}
+
+		pending, err := Pending(dir)
+		if err != nil || pending == nil || pending.Version != "v1.2.0" {
+			t.Fatalf("Pending = %+v, %v", pending, err)
+		}
+		if err := MarkInstallStarted(dir); err != nil {
+			t.Fatal(err)
+		}
+		if pending, err := Pending(dir); err != nil || pending == nil || pending.InstallStarted.IsZero() {
+			t.Fatalf("install start not recorded: %+v, %v", pending, err)
+		}
+		if err := ClearPending(dir); err != nil {
+			t.Fatal(err)
+		}
+		if pending, _ := Pending(dir); pending != nil {
+			t.Errorf("expected no pending update after ClearPending")
+		}
+	})
+
+	t.Run("rejects bad signature", func(t *testing.T) {
+		_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+		_, badSig := signedChecksums(t, otherKey, asset)
+		rs := newReleaseServer(t, asset, checksums, badSig)
+
+		d := &Downloader{Dir: t.TempDir(), PublicKey: pub}
+		if _, err := d.Download(context.Background(), rs.info()); !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("expected ErrBadSignature, got %v", err)
+		}
+		if len(rs.ranges) != 0 {
+			t.Errorf("asset must not be downloaded when the signature is invalid")
+		}
+	})
+
+	t.Run("rejects checksum mismatch", func(t *testing.T) {
+		tampered := append([]byte("evil"), asset[4:]...)
+		rs := newReleaseServer(t, tampered, checksums, sig)
+		dir := t.TempDir()
+
+		d := &Downloader{Dir: dir, PublicKey: pub}
+		if _, err := d.Download(context.Background(), rs.info()); !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+		}
+		if pending, _ := Pending(dir); pending != nil {
+			t.Errorf("tampered update must not be staged")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "v1.2.0", "app.tar.gz.part")); !os.IsNotExist(err) {
+			t.Errorf("tampered partial download should be removed")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package updater
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Install replaces the running .app bundle with the one in the staged zip.
+// The new version runs once the app restarts.
+func Install(staged *StagedUpdate) (quit bool, err error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return false, err
+	}
+	i := strings.Index(exe, ".app/")
+	if i < 0 {
+		return false, fmt.Errorf("not running from an .app bundle")
+	}
+	bundle := exe[:i+len(".app")]
+
+	extractDir, err := os.MkdirTemp(filepath.Dir(bundle), ".update-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(extractDir)
+
+	// ditto keeps permissions, symlinks and extended attributes intact
+	if out, err := exec.Command("ditto", "-x", "-k", staged.Path, extractDir).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("failed to extract update: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	matches, _ := filepath.Glob(filepath.Join(extractDir, "*.app"))
+	if len(matches) != 1 {
+		return false, fmt.Errorf("update archive %s does not contain an .app bundle", staged.AssetName)
+	}
+
+	old := bundle + ".old"
+	_ = os.RemoveAll(old)
+	if err := os.Rename(bundle, old); err != nil {
+		return false, err
+	}
+	if err := os.Rename(matches[0], bundle); err != nil {
+		_ = os.Rename(old, bundle)
+		return false, err
+	}
+	_ = os.RemoveAll(old)
+	return false, nil
+}
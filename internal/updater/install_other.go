@@ -0,0 +1,64 @@
+//go:build !windows && !darwin
+
+package updater
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Install replaces the running executable with the one of the same name in
+// the staged .tar.gz. The new version runs once the app restarts.
+func Install(staged *StagedUpdate) (quit bool, err error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return false, err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return false, err
+	}
+
+	f, err := os.Open(staged.Path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return false, fmt.Errorf("update is not a .tar.gz: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return false, fmt.Errorf("update archive %s does not contain %s", staged.AssetName, filepath.Base(exe))
+		}
+		if err != nil {
+			return false, err
+		}
+		if hdr.Typeflag != tar.TypeReg || filepath.Base(hdr.Name) != filepath.Base(exe) {
+			continue
+		}
+
+		tmp := exe + ".new"
+		out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return false, err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			os.Remove(tmp)
+			return false, err
+		}
+		if err := out.Close(); err != nil {
+			os.Remove(tmp)
+			return false, err
+		}
+		return false, os.Rename(tmp, exe)
+	}
+}
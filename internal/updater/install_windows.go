@@ -0,0 +1,19 @@
+package updater
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Install launches the staged NSIS installer. It returns quit=true because
+// the running application must exit for the installer to replace it.
+func Install(staged *StagedUpdate) (quit bool, err error) {
+	if !strings.HasSuffix(strings.ToLower(staged.Path), ".exe") {
+		return false, fmt.Errorf("cannot install %s: expected an installer .exe", staged.AssetName)
+	}
+	if err := exec.Command(staged.Path).Start(); err != nil {
+		return false, fmt.Errorf("failed to launch installer: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,125 @@
+package updater
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// RenderMarkdown converts release notes to HTML. It handles the subset GitHub
+// release notes use: headings, lists, fenced code, paragraphs, inline code,
+// bold, italics and links. All text is escaped and only http(s) links are
+// kept, so the result is safe to show in the frontend.
+func RenderMarkdown(src string) string {
+	var b strings.Builder
+	var paragraph []string
+	list := "" // "ul" or "ol" while inside a list
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(kind string) {
+		flushParagraph()
+		if list != kind {
+			closeList()
+			b.WriteString("<" + kind + ">\n")
+			list = kind
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				b.WriteString("</code></pre>\n")
+			} else {
+				flushParagraph()
+				closeList()
+				b.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case headingRe.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			m := headingRe.FindStringSubmatch(trimmed)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", len(m[1]), renderInline(m[2]), len(m[1]))
+		case bulletRe.MatchString(trimmed):
+			openList("ul")
+			b.WriteString("<li>" + renderInline(bulletRe.ReplaceAllString(trimmed, "")) + "</li>\n")
+		case orderedRe.MatchString(trimmed):
+			openList("ol")
+			b.WriteString("<li>" + renderInline(orderedRe.ReplaceAllString(trimmed, "")) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	if inCode {
+		b.WriteString("</code></pre>\n")
+	}
+	flushParagraph()
+	closeList()
+	return b.String()
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	bulletRe  = regexp.MustCompile(`^[-*+]\s+`)
+	orderedRe = regexp.MustCompile(`^\d+[.)]\s+`)
+
+	linkRe   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldRe   = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	italicRe = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// renderInline escapes text and renders inline markup. Code spans are split
+// out first so nothing inside them is interpreted.
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	var b strings.Builder
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		// Odd parts are code spans, unless the last backtick is unmatched
+		if i%2 == 1 && i < len(parts)-1 {
+			b.WriteString("<code>" + escaped + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			b.WriteString("`")
+		}
+		escaped = linkRe.ReplaceAllStringFunc(escaped, func(m string) string {
+			sub := linkRe.FindStringSubmatch(m)
+			url := html.UnescapeString(sub[2])
+			if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+				return sub[1]
+			}
+			return `<a href="` + html.EscapeString(url) + `">` + sub[1] + "</a>"
+		})
+		escaped = boldRe.ReplaceAllString(escaped, "<strong>$1</strong>")
+		escaped = italicRe.ReplaceAllString(escaped, "<em>$1</em>")
+		b.WriteString(escaped)
+	}
+	return b.String()
+}
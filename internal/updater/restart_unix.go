@@ -0,0 +1,22 @@
+//go:build !windows
+
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Restart replaces the process with a new start of its executable, which
+// runs the version Install put in its place. It only returns on failure.
+func Restart() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	return syscall.Exec(exe, os.Args, os.Environ())
+}
@@ -0,0 +1,18 @@
+package updater
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Restart starts a new process of the executable with the same arguments.
+// The caller exits once it returns without error.
+func Restart() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Start()
+}
@@ -1,18 +1,55 @@
 package updater
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"runtime"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// DefaultRepository is the GitHub repository releases are published to
+const DefaultRepository = "liuc-c/slidev-ai"
+
+// DefaultAPIBaseURL is the GitHub REST API endpoint
+const DefaultAPIBaseURL = "https://api.github.com"
+
+// DefaultTimeout bounds each request to the releases API
+const DefaultTimeout = 15 * time.Second
+
+// ErrRateLimited is returned while the GitHub API rate limit is exhausted
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// RateLimitError reports when the rate limit resets
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", ErrRateLimited, e.Reset.Local().Format(time.Kitchen))
+}
+
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+
+// Channel selects which releases are offered as updates
+type Channel string
+
+const (
+	ChannelStable Channel = "stable" // Only final releases
+	ChannelBeta   Channel = "beta"   // Final releases and prereleases
 )
 
 type Release struct {
-	TagName string  `json:"tag_name"`
-	Assets  []Asset `json:"assets"`
-	Body    string  `json:"body"`
-	HTMLURL string  `json:"html_url"`
+	TagName    string  `json:"tag_name"`
+	Name       string  `json:"name"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+	Body       string  `json:"body"`
+	HTMLURL    string  `json:"html_url"`
 }
 
 type Asset struct {
@@ -21,60 +58,193 @@ type Asset struct {
 }
 
 type UpdateInfo struct {
-	Available   bool
-	Version     string
-	DownloadURL string
-	ReleaseURL  string
-	Body        string
+	Available         bool
+	CurrentVersion    string
+	Version           string
+	Prerelease        bool
+	AssetName         string
+	AssetKind         AssetKind
+	DownloadURL       string
+	NoCompatibleAsset bool   // A newer release exists but has no download for this platform
+	ChecksumsURL      string // SHA-256 checksums file published with the release
+	SignatureURL      string // ed25519 signature of the checksums file
+	ReleaseURL        string
+	Body              string
+	NotesHTML         string // Body rendered from markdown
+	Skipped           bool   // Version is the one the user chose to skip
+}
+
+// Options configures where and how updates are looked up
+type Options struct {
+	Repository  string       // "owner/repo", DefaultRepository when empty
+	Channel     Channel      // ChannelStable when empty
+	APIBaseURL  string       // DefaultAPIBaseURL when empty
+	Client      *http.Client // A client with DefaultTimeout when nil
+	Platform    Platform     // CurrentPlatform() when empty
+	SkipVersion string       // Marks this version as skipped in the result
+}
+
+func (o Options) withDefaults() Options {
+	if o.Repository == "" {
+		o.Repository = DefaultRepository
+	}
+	if o.Channel == "" {
+		o.Channel = ChannelStable
+	}
+	if o.APIBaseURL == "" {
+		o.APIBaseURL = DefaultAPIBaseURL
+	}
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if o.Platform == (Platform{}) {
+		o.Platform = CurrentPlatform()
+	}
+	return o
 }
 
-// CheckForUpdates checks for updates on GitHub
-func CheckForUpdates(currentVersion, repoOwner, repoName string) (*UpdateInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", repoOwner, repoName)
-	resp, err := http.Get(url)
+// CheckForUpdates looks for the newest release on the configured channel that
+// is newer than currentVersion. Drafts are always skipped, prereleases unless
+// the channel is beta.
+func CheckForUpdates(currentVersion string, opts Options) (*UpdateInfo, error) {
+	opts = opts.withDefaults()
+	res, err := fetchReleases(context.Background(), opts, "")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return selectUpdate(currentVersion, res.Releases, opts)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch release info: %s", resp.Status)
+// selectUpdate picks the update to offer from a list of releases
+func selectUpdate(currentVersion string, releases []Release, opts Options) (*UpdateInfo, error) {
+	if opts.Channel != ChannelStable && opts.Channel != ChannelBeta {
+		return nil, fmt.Errorf("unknown update channel %q", opts.Channel)
+	}
+	current, err := ParseVersion(currentVersion)
+	if err != nil {
+		return nil, fmt.Errorf("current version: %w", err)
+	}
+
+	var latest *Release
+	var latestVersion Version
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft {
+			continue
+		}
+		v, err := ParseVersion(release.TagName)
+		if err != nil {
+			continue // Not a version tag
+		}
+		if (release.Prerelease || v.IsPrerelease()) && opts.Channel != ChannelBeta {
+			continue
+		}
+		if latest == nil || v.Compare(latestVersion) > 0 {
+			latest, latestVersion = release, v
+		}
+	}
+
+	info := &UpdateInfo{CurrentVersion: currentVersion}
+	if latest == nil || latestVersion.Compare(current) <= 0 {
+		return info, nil
 	}
 
-	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	info.Available = true
+	info.Version = latest.TagName
+	info.Prerelease = latest.Prerelease || latestVersion.IsPrerelease()
+	if asset, assetInfo, err := SelectAsset(latest.Assets, opts.Platform); err == nil {
+		info.AssetName = asset.Name
+		info.AssetKind = assetInfo.Kind
+		info.DownloadURL = asset.BrowserDownloadURL
+	} else {
+		info.NoCompatibleAsset = true
+	}
+	info.ChecksumsURL, info.SignatureURL = checksumAssets(latest.Assets)
+	info.ReleaseURL = latest.HTMLURL
+	info.Body = latest.Body
+	info.NotesHTML = RenderMarkdown(latest.Body)
+	if opts.SkipVersion != "" {
+		if skipped, err := ParseVersion(opts.SkipVersion); err == nil && skipped.Compare(latestVersion) == 0 {
+			info.Skipped = true
+		}
+	}
+	return info, nil
+}
+
+// fetchResult is a response of the releases API
+type fetchResult struct {
+	Releases       []Release
+	ETag           string
+	NotModified    bool      // The releases are unchanged since the ETag passed in
+	RateLimitReset time.Time // Set when the response used up the rate limit
+}
+
+// releasesURL returns the releases API endpoint of the configured repository
+func releasesURL(opts Options) (string, error) {
+	owner, repo, ok := strings.Cut(opts.Repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", fmt.Errorf("invalid repository %q: expected owner/repo", opts.Repository)
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/releases?per_page=30", strings.TrimRight(opts.APIBaseURL, "/"), owner, repo), nil
+}
+
+// fetchReleases lists the repository's releases. With an etag the request is
+// conditional and NotModified is set when nothing changed.
+func fetchReleases(ctx context.Context, opts Options, etag string) (*fetchResult, error) {
+	url, err := releasesURL(opts)
+	if err != nil {
 		return nil, err
 	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
-	// Simple version comparison (string based for now, assuming vX.Y.Z)
-	if release.TagName == currentVersion || release.TagName == "v"+currentVersion {
-		return &UpdateInfo{Available: false}, nil
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// Determine asset name based on OS
-	var assetNamePattern string
-	switch runtime.GOOS {
-	case "windows":
-		assetNamePattern = ".exe" // or setup.exe
-	case "darwin":
-		assetNamePattern = ".zip" // The CI builds a .zip of the .app bundle
-	default:
-		assetNamePattern = ".tar.gz"
+	res := &fetchResult{ETag: resp.Header.Get("ETag")}
+	reset := rateLimitReset(resp.Header)
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		res.RateLimitReset = reset
 	}
 
-	var downloadURL string
-	for _, asset := range release.Assets {
-		if strings.Contains(strings.ToLower(asset.Name), assetNamePattern) {
-			downloadURL = asset.BrowserDownloadURL
-			break
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		res.NotModified = true
+		res.ETag = etag
+		return res, nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		if retry, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			reset = time.Now().Add(time.Duration(retry) * time.Second)
+		}
+		if reset.IsZero() {
+			reset = time.Now().Add(time.Hour)
 		}
+		return nil, &RateLimitError{Reset: reset}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch release info: %s", resp.Status)
 	}
 
-	return &UpdateInfo{
-		Available:   true,
-		Version:     release.TagName,
-		DownloadURL: downloadURL,
-		ReleaseURL:  release.HTMLURL,
-		Body:        release.Body,
-	}, nil
+	if err := json.NewDecoder(resp.Body).Decode(&res.Releases); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// rateLimitReset reads the X-RateLimit-Reset header (unix seconds)
+func rateLimitReset(h http.Header) time.Time {
+	sec, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
 }
@@ -0,0 +1,92 @@
+package updater
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	// Ordered by increasing precedence, from the semver spec
+	ordered := []string{
+		"v0.0.0-dev",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"v1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, err := ParseVersion(ordered[i])
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %v", ordered[i], err)
+		}
+		b, err := ParseVersion(ordered[i+1])
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %v", ordered[i+1], err)
+		}
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	if v, _ := ParseVersion("1.2.3+build.5"); v.Compare(Version{Major: 1, Minor: 2, Patch: 3}) != 0 {
+		t.Errorf("build metadata must not affect precedence")
+	}
+	for _, bad := range []string{"", "1.2", "1.2.x", "01.2.3", "1.2.3-", "1.2.3-a..b", "latest"} {
+		if _, err := ParseVersion(bad); err == nil {
+			t.Errorf("expected ParseVersion(%q) to fail", bad)
+		}
+	}
+}
+
+func TestCheckForUpdatesChannels(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.3.0", Draft: true},
+		{TagName: "v1.2.0-beta.1", Prerelease: true, HTMLURL: "https://example.com/beta"},
+		{TagName: "v1.1.0", HTMLURL: "https://example.com/stable"},
+		{TagName: "nightly"},
+		{TagName: "v1.0.0"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/decks/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		current   string
+		channel   Channel
+		available bool
+		version   string
+	}{
+		{"v1.0.0", ChannelStable, true, "v1.1.0"},
+		{"v1.0.0", ChannelBeta, true, "v1.2.0-beta.1"},
+		{"v1.1.0", ChannelStable, false, ""},
+		{"v1.2.0", ChannelBeta, false, ""}, // Newer than anything published: no downgrade
+		{"v1.2.0-alpha", ChannelBeta, true, "v1.2.0-beta.1"},
+	}
+	for _, tt := range tests {
+		info, err := CheckForUpdates(tt.current, Options{Repository: "acme/decks", Channel: tt.channel, APIBaseURL: srv.URL})
+		if err != nil {
+			t.Fatalf("CheckForUpdates(%s, %s): %v", tt.current, tt.channel, err)
+		}
+		if info.Available != tt.available || info.Version != tt.version {
+			t.Errorf("CheckForUpdates(%s, %s) = %v %q, want %v %q", tt.current, tt.channel, info.Available, info.Version, tt.available, tt.version)
+		}
+	}
+
+	if _, err := CheckForUpdates("v1.0.0", Options{Repository: "not-a-repo", APIBaseURL: srv.URL}); err == nil {
+		t.Errorf("expected invalid repository error")
+	}
+}
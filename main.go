@@ -2,6 +2,14 @@ package main
 
 import (
 	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"slidev-studio-ai/internal/cli"
+	"slidev-studio-ai/internal/config"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -15,11 +23,41 @@ var assets embed.FS
 var Version = "v0.0.0-dev"
 
 func main() {
+	// Command line flags override env vars and the config file
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	config.BindFlags(flags)
+	args, err := parseFlags(flags, os.Args[1:])
+	if err == flag.ErrHelp {
+		flags.Usage()
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Subcommands run headless, without opening a window
+	if len(args) > 0 {
+		cli.Version = Version
+		os.Exit(cli.Run(args, os.Stdout, os.Stderr))
+	}
+
+	// Initialize config
+	if _, err := config.Load(); err != nil {
+		fmt.Printf("Error loading config from %s: %v\n", config.Path(), err)
+	}
+
+	// A staged update is installed before the window opens, so it runs now
+	if installPendingUpdate(Version) {
+		os.Exit(0)
+	}
+
 	// Create an instance of the app structure
 	app := NewApp(Version)
 
 	// Create application with options
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:  "slidev-studio-ai",
 		Width:  1024,
 		Height: 768,
@@ -38,3 +76,37 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// parseFlags parses the global flags and returns the remaining arguments.
+// Flags the app does not know, such as the -psn_* process serial number
+// macOS passes to apps opened from the Finder, are skipped when no
+// subcommand follows, so the GUI still starts.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	flags.SetOutput(io.Discard)
+	defer flags.SetOutput(nil)
+	var unknown error
+	for {
+		err := flags.Parse(args)
+		if err == nil {
+			break
+		}
+		if err == flag.ErrHelp {
+			return nil, err
+		}
+		// The flag that failed is the argument before the unparsed ones; a
+		// known flag failed on its value
+		name := args[len(args)-flags.NArg()-1]
+		name, _, _ = strings.Cut(strings.TrimLeft(name, "-"), "=")
+		if flags.Lookup(name) != nil {
+			return nil, err
+		}
+		if unknown == nil {
+			unknown = err
+		}
+		args = flags.Args()
+	}
+	if unknown != nil && flags.NArg() > 0 {
+		return nil, unknown
+	}
+	return flags.Args(), nil
+}
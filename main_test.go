@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{args: nil, want: nil},
+		{args: []string{"-psn_0_1234567"}, want: []string{}},
+		{args: []string{"-psn_0_1234567", "-workspace", "/tmp/decks"}, want: []string{}},
+		{args: []string{"-workspace=/tmp/decks", "list", "-json"}, want: []string{"list", "-json"}},
+		{args: []string{"-unknown", "list"}, wantErr: true},
+		{args: []string{"-workspace"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		flags := flag.NewFlagSet("app", flag.ContinueOnError)
+		workspace := flags.String("workspace", "", "")
+		got, err := parseFlags(flags, tt.args)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("parseFlags(%q) = %q, %v", tt.args, got, err)
+		}
+		if !tt.wantErr && len(tt.args) > 1 && *workspace != "/tmp/decks" {
+			t.Errorf("parseFlags(%q) lost -workspace: %q", tt.args, *workspace)
+		}
+	}
+}